@@ -20,6 +20,8 @@ func newChatsCmd(flags *rootFlags) *cobra.Command {
 	}
 	cmd.AddCommand(newChatsListCmd(flags))
 	cmd.AddCommand(newChatsShowCmd(flags))
+	cmd.AddCommand(newChatsHistoryCmd(flags))
+	cmd.AddCommand(newChatsLabelCmd(flags))
 	cmd.AddCommand(newChatStateCmd(flags, chatStateAction{
 		use: "archive", short: "Archive a chat",
 		run: func(ctx context.Context, a *appHandle, jid string) error { return a.app.ArchiveChat(ctx, a.jid, true) },
@@ -59,6 +61,10 @@ func newChatsListCmd(flags *rootFlags) *cobra.Command {
 	var pinned, noPinned bool
 	var muted, noMuted bool
 	var unread, noUnread bool
+	var tags []string
+	var hasTag, noTag bool
+	var labels []string
+	var noLabel bool
 
 	cmd := &cobra.Command{
 		Use:   "list",
@@ -76,6 +82,12 @@ func newChatsListCmd(flags *rootFlags) *cobra.Command {
 			if unread && noUnread {
 				return fmt.Errorf("--unread and --no-unread are mutually exclusive")
 			}
+			if hasTag && noTag {
+				return fmt.Errorf("--has-tag and --no-tag are mutually exclusive")
+			}
+			if len(labels) > 0 && noLabel {
+				return fmt.Errorf("--label and --no-label are mutually exclusive")
+			}
 
 			ctx, cancel := withTimeout(context.Background(), flags)
 			defer cancel()
@@ -91,13 +103,38 @@ func newChatsListCmd(flags *rootFlags) *cobra.Command {
 			f.Pinned = boolFilter(pinned, noPinned)
 			f.Muted = boolFilter(muted, noMuted)
 			f.Unread = boolFilter(unread, noUnread)
+			if len(tags) > 0 {
+				f.Tags = &tags
+			}
+			f.HasTag = boolFilter(hasTag, noTag)
+			if len(labels) > 0 {
+				f.Labels = &labels
+			}
+			if noLabel {
+				v := false
+				f.HasLabel = &v
+			}
 
 			chats, err := a.DB().ListChats(f)
 			if err != nil {
 				return err
 			}
+
+			jids := make([]string, len(chats))
+			for i, c := range chats {
+				jids[i] = c.JID
+			}
+			labelsByJID, err := a.DB().ListChatLabelsForJIDs(jids)
+			if err != nil {
+				return err
+			}
+
 			if flags.asJSON {
-				return out.WriteJSON(os.Stdout, chats)
+				withLabels := make([]chatWithLabels, 0, len(chats))
+				for _, c := range chats {
+					withLabels = append(withLabels, chatWithLabels{Chat: c, Labels: labelsByJID[c.JID]})
+				}
+				return out.WriteJSON(os.Stdout, withLabels)
 			}
 
 			w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
@@ -107,7 +144,7 @@ func newChatsListCmd(flags *rootFlags) *cobra.Command {
 				if name == "" {
 					name = c.JID
 				}
-				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", c.Kind, truncate(name, 28), c.JID, c.LastMessageTS.Local().Format("2006-01-02 15:04:05"), chatFlagsString(c))
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", c.Kind, truncate(name, 28), c.JID, c.LastMessageTS.Local().Format("2006-01-02 15:04:05"), chatFlagsString(c, labelsByJID[c.JID]))
 			}
 			_ = w.Flush()
 			return nil
@@ -123,6 +160,11 @@ func newChatsListCmd(flags *rootFlags) *cobra.Command {
 	cmd.Flags().BoolVar(&noMuted, "no-muted", false, "exclude muted chats")
 	cmd.Flags().BoolVar(&unread, "unread", false, "show only unread chats")
 	cmd.Flags().BoolVar(&noUnread, "no-unread", false, "exclude unread chats")
+	cmd.Flags().StringArrayVar(&tags, "tag", nil, "show only chats with this contact tag (repeatable, matches any)")
+	cmd.Flags().BoolVar(&hasTag, "has-tag", false, "show only chats that have any tag")
+	cmd.Flags().BoolVar(&noTag, "no-tag", false, "show only chats that have no tags")
+	cmd.Flags().StringArrayVar(&labels, "label", nil, "show only chats with this label (repeatable, matches any)")
+	cmd.Flags().BoolVar(&noLabel, "no-label", false, "show only chats that have no labels")
 	return cmd
 }
 
@@ -148,12 +190,16 @@ func newChatsShowCmd(flags *rootFlags) *cobra.Command {
 			if err != nil {
 				return err
 			}
+			chatLabels, err := a.DB().ListChatLabels(jid)
+			if err != nil {
+				return err
+			}
 			if flags.asJSON {
-				return out.WriteJSON(os.Stdout, c)
+				return out.WriteJSON(os.Stdout, chatWithLabels{Chat: c, Labels: chatLabels})
 			}
-			fmt.Fprintf(os.Stdout, "JID: %s\nKind: %s\nName: %s\nLast: %s\nArchived: %t\nPinned: %t\nMuted: %t\nUnread: %t\n",
+			fmt.Fprintf(os.Stdout, "JID: %s\nKind: %s\nName: %s\nLast: %s\nArchived: %t\nPinned: %t\nMuted: %t%s\nUnread: %t\nLabels: %s\n",
 				c.JID, c.Kind, c.Name, c.LastMessageTS.Local().Format(time.RFC3339),
-				c.Archived, c.Pinned, c.Muted(), c.Unread)
+				c.Archived, c.Pinned, c.Muted(), muteRemainingSuffix(c), c.Unread, strings.Join(chatLabels, ","))
 			return nil
 		},
 	}
@@ -161,6 +207,100 @@ func newChatsShowCmd(flags *rootFlags) *cobra.Command {
 	return cmd
 }
 
+// newChatsHistoryCmd pages through a single chat's messages using opaque
+// (ts, rowid) cursors rather than OFFSET, the way IRCv3 CHATHISTORY verbs
+// (BEFORE/AFTER/LATEST/AROUND/BETWEEN) address a timeline. Unlike the
+// top-level `wacli history` command (which anchors on a msg_id or bare
+// timestamp for one-shot lookups), this returns next/prev cursors so a
+// script can keep paging a long chat without re-scanning it.
+func newChatsHistoryCmd(flags *rootFlags) *cobra.Command {
+	var jid string
+	var before, after, around, between string
+	var latest bool
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Page through a chat's messages with opaque cursors",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if jid == "" {
+				return fmt.Errorf("--jid is required")
+			}
+			set := 0
+			for _, v := range []bool{before != "", after != "", around != "", between != "", latest} {
+				if v {
+					set++
+				}
+			}
+			if set != 1 {
+				return fmt.Errorf("exactly one of --before, --after, --around, --between, or --latest is required")
+			}
+
+			ctx, cancel := withTimeout(context.Background(), flags)
+			defer cancel()
+
+			a, lk, err := newApp(ctx, flags, false, false)
+			if err != nil {
+				return err
+			}
+			defer closeApp(a, lk)
+
+			var page store.ChatHistoryPage
+			switch {
+			case before != "":
+				page, err = a.ChatHistoryBefore(jid, before, limit)
+			case after != "":
+				page, err = a.ChatHistoryAfter(jid, after, limit)
+			case around != "":
+				page, err = a.ChatHistoryAround(jid, around, limit)
+			case between != "":
+				parts := strings.SplitN(between, ",", 2)
+				if len(parts) != 2 {
+					return fmt.Errorf("--between must be \"<start>,<end>\"")
+				}
+				startT, perr := parseFlexTime(strings.TrimSpace(parts[0]))
+				if perr != nil {
+					return fmt.Errorf("invalid --between start: %w", perr)
+				}
+				endT, perr := parseFlexTime(strings.TrimSpace(parts[1]))
+				if perr != nil {
+					return fmt.Errorf("invalid --between end: %w", perr)
+				}
+				page, err = a.ChatHistoryBetween(jid, startT, endT, limit)
+			case latest:
+				page, err = a.ChatHistoryLatest(jid, limit)
+			}
+			if err != nil {
+				return err
+			}
+
+			if flags.asJSON {
+				return out.WriteJSON(os.Stdout, map[string]any{
+					"messages":    page.Messages,
+					"next_cursor": page.NextCursor,
+					"prev_cursor": page.PrevCursor,
+				})
+			}
+			for _, m := range page.Messages {
+				fmt.Fprintf(os.Stdout, "[%s] %s (%s) %s: %s\n",
+					m.Timestamp.Local().Format("2006-01-02 15:04:05"), m.ChatJID, m.MsgID, m.SenderName, m.Text)
+			}
+			if page.NextCursor != "" {
+				fmt.Fprintf(os.Stdout, "next: %s\n", page.NextCursor)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&jid, "jid", "", "chat JID")
+	cmd.Flags().StringVar(&before, "before", "", "cursor: page of messages strictly older than this")
+	cmd.Flags().StringVar(&after, "after", "", "cursor: page of messages strictly newer than this")
+	cmd.Flags().StringVar(&around, "around", "", "cursor: page surrounding this message")
+	cmd.Flags().StringVar(&between, "between", "", "\"<start>,<end>\" time range (RFC3339 or 2006-01-02)")
+	cmd.Flags().BoolVar(&latest, "latest", false, "most recent page")
+	cmd.Flags().IntVar(&limit, "limit", 50, "max messages to return")
+	return cmd
+}
+
 func boolFilter(pos, neg bool) *bool {
 	if pos {
 		v := true
@@ -173,7 +313,14 @@ func boolFilter(pos, neg bool) *bool {
 	return nil
 }
 
-func chatFlagsString(c store.Chat) string {
+// chatWithLabels wraps a Chat with its chat_labels for JSON output, since
+// labels live in a separate table rather than on the Chat row itself.
+type chatWithLabels struct {
+	store.Chat
+	Labels []string `json:"labels,omitempty"`
+}
+
+func chatFlagsString(c store.Chat, labels []string) string {
 	var flags []string
 	if c.Pinned {
 		flags = append(flags, "pinned")
@@ -182,10 +329,143 @@ func chatFlagsString(c store.Chat) string {
 		flags = append(flags, "archived")
 	}
 	if c.Muted() {
-		flags = append(flags, "muted")
+		flags = append(flags, "muted"+muteRemainingSuffix(c))
 	}
 	if c.Unread {
 		flags = append(flags, "unread")
 	}
+	for _, label := range labels {
+		flags = append(flags, "label:"+label)
+	}
 	return strings.Join(flags, ",")
 }
+
+// newChatsLabelCmd groups the chat_labels CRUD commands, the way
+// newSearchCmd groups save/run/list for saved searches.
+func newChatsLabelCmd(flags *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "label",
+		Short: "Manage chat labels",
+	}
+	cmd.AddCommand(newChatsLabelAddCmd(flags))
+	cmd.AddCommand(newChatsLabelRemoveCmd(flags))
+	cmd.AddCommand(newChatsLabelListCmd(flags))
+	return cmd
+}
+
+func newChatsLabelAddCmd(flags *rootFlags) *cobra.Command {
+	var jid, label, color string
+	var sortOrder int
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "Attach a label to a chat",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if jid == "" || label == "" {
+				return fmt.Errorf("--jid and --label are required")
+			}
+			ctx, cancel := withTimeout(context.Background(), flags)
+			defer cancel()
+
+			a, lk, err := newApp(ctx, flags, false, false)
+			if err != nil {
+				return err
+			}
+			defer closeApp(a, lk)
+
+			if err := a.DB().AddChatLabel(jid, label, color, sortOrder); err != nil {
+				return err
+			}
+			if flags.asJSON {
+				return out.WriteJSON(os.Stdout, map[string]any{"jid": jid, "label": label, "ok": true})
+			}
+			fmt.Fprintln(os.Stdout, "OK")
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&jid, "jid", "", "chat JID")
+	cmd.Flags().StringVar(&label, "label", "", "label to attach")
+	cmd.Flags().StringVar(&color, "color", "", "display color (freeform, e.g. a hex code or name)")
+	cmd.Flags().IntVar(&sortOrder, "sort-order", 0, "position among this chat's other labels (lower first)")
+	return cmd
+}
+
+func newChatsLabelRemoveCmd(flags *rootFlags) *cobra.Command {
+	var jid, label string
+	cmd := &cobra.Command{
+		Use:   "remove",
+		Short: "Detach a label from a chat",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if jid == "" || label == "" {
+				return fmt.Errorf("--jid and --label are required")
+			}
+			ctx, cancel := withTimeout(context.Background(), flags)
+			defer cancel()
+
+			a, lk, err := newApp(ctx, flags, false, false)
+			if err != nil {
+				return err
+			}
+			defer closeApp(a, lk)
+
+			if err := a.DB().RemoveChatLabel(jid, label); err != nil {
+				return err
+			}
+			if flags.asJSON {
+				return out.WriteJSON(os.Stdout, map[string]any{"jid": jid, "label": label, "ok": true})
+			}
+			fmt.Fprintln(os.Stdout, "OK")
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&jid, "jid", "", "chat JID")
+	cmd.Flags().StringVar(&label, "label", "", "label to detach")
+	return cmd
+}
+
+func newChatsLabelListCmd(flags *rootFlags) *cobra.Command {
+	var jid string
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List a chat's labels",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if jid == "" {
+				return fmt.Errorf("--jid is required")
+			}
+			ctx, cancel := withTimeout(context.Background(), flags)
+			defer cancel()
+
+			a, lk, err := newApp(ctx, flags, false, false)
+			if err != nil {
+				return err
+			}
+			defer closeApp(a, lk)
+
+			labels, err := a.DB().ListChatLabels(jid)
+			if err != nil {
+				return err
+			}
+			if flags.asJSON {
+				return out.WriteJSON(os.Stdout, labels)
+			}
+			for _, label := range labels {
+				fmt.Fprintln(os.Stdout, label)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&jid, "jid", "", "chat JID")
+	return cmd
+}
+
+// muteRemainingSuffix renders the time left on c's mute as " (for 7h30m)",
+// " (forever)" if it has no expiry, or "" if c isn't muted.
+func muteRemainingSuffix(c store.Chat) string {
+	remaining, forever, ok := c.MutedRemaining(time.Now())
+	if !ok {
+		return ""
+	}
+	if forever {
+		return " (forever)"
+	}
+	return fmt.Sprintf(" (for %s)", remaining.Round(time.Second))
+}
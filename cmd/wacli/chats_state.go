@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -111,9 +112,36 @@ func newChatStateCmd(flags *rootFlags, action chatStateAction) *cobra.Command {
 	return cmd
 }
 
+// parseMuteDuration parses a Go duration string (e.g. "8h", "90m") as well
+// as the friendly day/week suffixes "1d" and "2w", since mute schedules are
+// usually expressed in whole days rather than hours.
+func parseMuteDuration(s string) (time.Duration, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+	if n := len(s); n > 1 {
+		unit := s[n-1]
+		if unit == 'd' || unit == 'w' {
+			count, err := strconv.Atoi(s[:n-1])
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			day := 24 * time.Hour
+			if unit == 'w' {
+				return time.Duration(count) * 7 * day, nil
+			}
+			return time.Duration(count) * day, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid duration %q", s)
+}
+
 func newChatsMuteCmd(flags *rootFlags) *cobra.Command {
 	var jidStr string
 	var durStr string
+	var forStr string
+	var untilStr string
+	var forever bool
 	var noIPC bool
 	cmd := &cobra.Command{
 		Use:   "mute",
@@ -123,6 +151,41 @@ func newChatsMuteCmd(flags *rootFlags) *cobra.Command {
 				return fmt.Errorf("--jid is required")
 			}
 
+			set := 0
+			for _, v := range []bool{forStr != "", untilStr != "", forever} {
+				if v {
+					set++
+				}
+			}
+			if set > 1 {
+				return fmt.Errorf("--for, --until, and --forever are mutually exclusive")
+			}
+
+			var resolvedDur time.Duration
+			switch {
+			case forever, set == 0:
+				resolvedDur = 0
+			case forStr != "":
+				d, err := parseMuteDuration(forStr)
+				if err != nil {
+					return fmt.Errorf("invalid --for: %w", err)
+				}
+				resolvedDur = d
+			case untilStr != "":
+				t, err := parseFlexTime(untilStr)
+				if err != nil {
+					return fmt.Errorf("invalid --until: %w", err)
+				}
+				resolvedDur = time.Until(t)
+				if resolvedDur <= 0 {
+					return fmt.Errorf("--until %s is in the past", untilStr)
+				}
+			}
+			durStr = ""
+			if resolvedDur > 0 {
+				durStr = resolvedDur.String()
+			}
+
 			// Try IPC first if not disabled
 			if !noIPC {
 				storeDir := flags.storeDir
@@ -177,7 +240,7 @@ func newChatsMuteCmd(flags *rootFlags) *cobra.Command {
 			if strings.TrimSpace(durStr) != "" {
 				dur, err = time.ParseDuration(durStr)
 				if err != nil {
-					return fmt.Errorf("invalid --duration: %w", err)
+					return fmt.Errorf("invalid mute duration: %w", err)
 				}
 			}
 
@@ -198,7 +261,9 @@ func newChatsMuteCmd(flags *rootFlags) *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVar(&jidStr, "jid", "", "chat JID")
-	cmd.Flags().StringVar(&durStr, "duration", "", "mute duration (e.g. 8h, 24h, 168h); empty = forever")
+	cmd.Flags().StringVar(&forStr, "for", "", "mute duration, Go duration or friendly day/week form (e.g. 8h, 1d, 2w)")
+	cmd.Flags().StringVar(&untilStr, "until", "", "mute until this absolute time (RFC3339, 2006-01-02T15:04, or 2006-01-02)")
+	cmd.Flags().BoolVar(&forever, "forever", false, "mute indefinitely")
 	cmd.Flags().BoolVar(&noIPC, "no-ipc", false, "skip IPC and use direct connection")
 	return cmd
 }
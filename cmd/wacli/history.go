@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/wacli/internal/app"
+	"github.com/steipete/wacli/internal/out"
+	"github.com/steipete/wacli/internal/store"
+)
+
+func newHistoryCmd(flags *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Scroll chat history deterministically (IRCv3 CHATHISTORY-style)",
+	}
+	cmd.AddCommand(newHistoryVerbCmd(flags, "before", "Messages strictly older than an anchor", func(a *app.App, jid, anchor, anchorEnd string, limit int) ([]store.Message, error) {
+		return a.HistoryBefore(jid, store.ParseHistoryAnchor(anchor), limit)
+	}))
+	cmd.AddCommand(newHistoryVerbCmd(flags, "after", "Messages strictly newer than an anchor", func(a *app.App, jid, anchor, anchorEnd string, limit int) ([]store.Message, error) {
+		return a.HistoryAfter(jid, store.ParseHistoryAnchor(anchor), limit)
+	}))
+	cmd.AddCommand(newHistoryVerbCmd(flags, "latest", "Most recent messages", func(a *app.App, jid, anchor, anchorEnd string, limit int) ([]store.Message, error) {
+		return a.HistoryLatest(jid, limit)
+	}))
+	cmd.AddCommand(newHistoryVerbCmd(flags, "around", "Messages surrounding an anchor", func(a *app.App, jid, anchor, anchorEnd string, limit int) ([]store.Message, error) {
+		return a.HistoryAround(jid, store.ParseHistoryAnchor(anchor), limit)
+	}))
+	cmd.AddCommand(newHistoryBetweenCmd(flags))
+	return cmd
+}
+
+type historyVerbFunc func(a *app.App, jid, anchor, anchorEnd string, limit int) ([]store.Message, error)
+
+func newHistoryVerbCmd(flags *rootFlags, use, short string, run historyVerbFunc) *cobra.Command {
+	var jid string
+	var anchor string
+	var limit int
+
+	needsAnchor := use != "latest"
+
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if jid == "" {
+				return fmt.Errorf("--jid is required")
+			}
+			if needsAnchor && anchor == "" {
+				return fmt.Errorf("--anchor is required")
+			}
+
+			ctx, cancel := withTimeout(context.Background(), flags)
+			defer cancel()
+
+			a, lk, err := newApp(ctx, flags, false, false)
+			if err != nil {
+				return err
+			}
+			defer closeApp(a, lk)
+
+			msgs, err := run(a, jid, anchor, "", limit)
+			if err != nil {
+				return err
+			}
+			return writeHistory(flags, msgs)
+		},
+	}
+	cmd.Flags().StringVar(&jid, "jid", "", "chat JID")
+	if needsAnchor {
+		cmd.Flags().StringVar(&anchor, "anchor", "", "anchor msg_id or Unix timestamp")
+	}
+	cmd.Flags().IntVar(&limit, "limit", 50, "max messages to return")
+	return cmd
+}
+
+func newHistoryBetweenCmd(flags *rootFlags) *cobra.Command {
+	var jid string
+	var start, end string
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "between",
+		Short: "Messages within a time range",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if jid == "" || start == "" || end == "" {
+				return fmt.Errorf("--jid, --start, and --end are required")
+			}
+
+			ctx, cancel := withTimeout(context.Background(), flags)
+			defer cancel()
+
+			a, lk, err := newApp(ctx, flags, false, false)
+			if err != nil {
+				return err
+			}
+			defer closeApp(a, lk)
+
+			startT, err := parseFlexTime(start)
+			if err != nil {
+				return fmt.Errorf("invalid --start: %w", err)
+			}
+			endT, err := parseFlexTime(end)
+			if err != nil {
+				return fmt.Errorf("invalid --end: %w", err)
+			}
+
+			msgs, err := a.HistoryBetween(jid, startT, endT, limit)
+			if err != nil {
+				return err
+			}
+			return writeHistory(flags, msgs)
+		},
+	}
+	cmd.Flags().StringVar(&jid, "jid", "", "chat JID")
+	cmd.Flags().StringVar(&start, "start", "", "range start (RFC3339 or 2006-01-02)")
+	cmd.Flags().StringVar(&end, "end", "", "range end (RFC3339 or 2006-01-02)")
+	cmd.Flags().IntVar(&limit, "limit", 50, "max messages to return")
+	return cmd
+}
+
+func writeHistory(flags *rootFlags, msgs []store.Message) error {
+	if flags.asJSON {
+		return out.WriteJSON(os.Stdout, msgs)
+	}
+	for _, m := range msgs {
+		fmt.Fprintf(os.Stdout, "[%s] %s (%s) %s: %s\n",
+			m.Timestamp.Local().Format(time.RFC3339), m.ChatJID, m.MsgID, m.SenderName, m.Text)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/wacli/internal/config"
+	"github.com/steipete/wacli/internal/ipc"
+	"github.com/steipete/wacli/internal/out"
+)
+
+func newIPCCmd(flags *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ipc",
+		Short: "Manage the sync daemon's IPC socket",
+	}
+	cmd.AddCommand(newIPCTokenCmd(flags))
+	return cmd
+}
+
+func newIPCTokenCmd(flags *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: "Manage IPC auth tokens (storeDir/ipc_tokens.json)",
+	}
+	cmd.AddCommand(newIPCTokenAddCmd(flags))
+	cmd.AddCommand(newIPCTokenListCmd(flags))
+	cmd.AddCommand(newIPCTokenRevokeCmd(flags))
+	return cmd
+}
+
+func ipcStoreDir(flags *rootFlags) string {
+	storeDir := flags.storeDir
+	if storeDir == "" {
+		storeDir = config.DefaultStoreDir()
+	}
+	storeDir, _ = filepath.Abs(storeDir)
+	return storeDir
+}
+
+func newIPCTokenAddCmd(flags *rootFlags) *cobra.Command {
+	var name string
+	var allowedCommands []string
+	var allowedChats []string
+
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "Generate a new IPC token and print it once",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if name == "" {
+				return fmt.Errorf("--name is required")
+			}
+
+			ts, err := ipc.LoadTokenStore(ipcStoreDir(flags))
+			if err != nil {
+				return err
+			}
+
+			token, err := ts.Add(name, allowedCommands, allowedChats)
+			if err != nil {
+				return err
+			}
+
+			if flags.asJSON {
+				return out.WriteJSON(os.Stdout, map[string]any{
+					"name":  name,
+					"token": token,
+				})
+			}
+			fmt.Fprintf(os.Stdout, "Token for %q (save it now, it cannot be shown again):\n%s\n", name, token)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&name, "name", "", "label for this token")
+	cmd.Flags().StringSliceVar(&allowedCommands, "allow-command", nil, "restrict to these IPC commands (repeatable; default: all)")
+	cmd.Flags().StringSliceVar(&allowedChats, "allow-chat", nil, "restrict to these chat JIDs (repeatable; default: all)")
+	return cmd
+}
+
+func newIPCTokenListCmd(flags *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List registered IPC tokens (hashes only)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ts, err := ipc.LoadTokenStore(ipcStoreDir(flags))
+			if err != nil {
+				return err
+			}
+			entries := ts.List()
+
+			if flags.asJSON {
+				return out.WriteJSON(os.Stdout, entries)
+			}
+			for _, e := range entries {
+				commands := "all"
+				if len(e.AllowedCommands) > 0 {
+					commands = strings.Join(e.AllowedCommands, ",")
+				}
+				chats := "all"
+				if len(e.AllowedChats) > 0 {
+					chats = strings.Join(e.AllowedChats, ",")
+				}
+				fmt.Fprintf(os.Stdout, "%s\tcreated=%s\tcommands=%s\tchats=%s\n",
+					e.Name, e.CreatedAt.Local().Format("2006-01-02T15:04:05"), commands, chats)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newIPCTokenRevokeCmd(flags *rootFlags) *cobra.Command {
+	var name string
+
+	cmd := &cobra.Command{
+		Use:   "revoke",
+		Short: "Revoke an IPC token",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if name == "" {
+				return fmt.Errorf("--name is required")
+			}
+			ts, err := ipc.LoadTokenStore(ipcStoreDir(flags))
+			if err != nil {
+				return err
+			}
+			if err := ts.Revoke(name); err != nil {
+				return err
+			}
+			if flags.asJSON {
+				return out.WriteJSON(os.Stdout, map[string]any{"revoked": name})
+			}
+			fmt.Fprintf(os.Stdout, "Revoked token %q\n", name)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&name, "name", "", "token label to revoke")
+	return cmd
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/wacli/internal/msgstore"
+	"github.com/steipete/wacli/internal/out"
+)
+
+func newLogsCmd(flags *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Manage the grep-able filesystem message log archive",
+	}
+	cmd.AddCommand(newLogsMigrateCmd(flags))
+	return cmd
+}
+
+func newLogsMigrateCmd(flags *rootFlags) *cobra.Command {
+	var from, to string
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Backfill messages/chats from the fs log archive into the DB",
+		Long:  "Parses the on-disk fs:<storeDir> log tree and backfills messages/chats rows\n(idempotent on msg_id), e.g.\n  wacli logs migrate --from fs:./wacli-store --to db",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if to != "db" {
+				return fmt.Errorf("--to must be \"db\" (only fs->db migration is supported)")
+			}
+			const prefix = "fs:"
+			if len(from) <= len(prefix) || from[:len(prefix)] != prefix {
+				return fmt.Errorf("--from must be fs:<path>")
+			}
+			fsPath := from[len(prefix):]
+
+			ctx, cancel := withTimeout(context.Background(), flags)
+			defer cancel()
+
+			a, lk, err := newApp(ctx, flags, false, false)
+			if err != nil {
+				return err
+			}
+			defer closeApp(a, lk)
+
+			n, err := msgstore.ImportFS(fsPath, a.DB())
+			if err != nil {
+				return err
+			}
+
+			if flags.asJSON {
+				return out.WriteJSON(os.Stdout, map[string]any{"imported": n})
+			}
+			fmt.Fprintf(os.Stdout, "Imported %d messages\n", n)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&from, "from", "", "source, e.g. fs:./wacli-store")
+	cmd.Flags().StringVar(&to, "to", "db", "destination (only \"db\" supported)")
+	return cmd
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/wacli/internal/out"
+	"github.com/steipete/wacli/internal/store"
+)
+
+func newMessagesCmd(flags *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "messages",
+		Short: "Query stored messages",
+	}
+	cmd.AddCommand(newMessagesSearchCmd(flags))
+	return cmd
+}
+
+func newMessagesSearchCmd(flags *rootFlags) *cobra.Command {
+	var query string
+	var chat string
+	var sender string
+	var since string
+	var until string
+	var fromMe, notFromMe bool
+	var limit int
+	var pageToken string
+
+	cmd := &cobra.Command{
+		Use:   "search",
+		Short: "Full-text search over stored messages",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if fromMe && notFromMe {
+				return fmt.Errorf("--from-me and --not-from-me are mutually exclusive")
+			}
+
+			ctx, cancel := withTimeout(context.Background(), flags)
+			defer cancel()
+
+			a, lk, err := newApp(ctx, flags, false, false)
+			if err != nil {
+				return err
+			}
+			defer closeApp(a, lk)
+
+			q := store.SearchQuery{Text: query, FromMe: boolFilter(fromMe, notFromMe)}
+			if strings.TrimSpace(chat) != "" {
+				q.ChatJID = &chat
+			}
+			if strings.TrimSpace(sender) != "" {
+				q.SenderJID = &sender
+			}
+			if since != "" {
+				t, err := parseFlexTime(since)
+				if err != nil {
+					return fmt.Errorf("invalid --since: %w", err)
+				}
+				q.Since = &t
+			}
+			if until != "" {
+				t, err := parseFlexTime(until)
+				if err != nil {
+					return fmt.Errorf("invalid --until: %w", err)
+				}
+				q.Until = &t
+			}
+
+			res, err := a.DB().SearchMessages(ctx, q, limit, pageToken)
+			if err != nil {
+				return err
+			}
+
+			if flags.asJSON {
+				return out.WriteJSON(os.Stdout, map[string]any{
+					"hits":            res.Hits,
+					"next_page_token": res.NextPageToken,
+				})
+			}
+
+			for _, h := range res.Hits {
+				fmt.Fprintf(os.Stdout, "[%s] %s (%s) %s: %s\n",
+					h.Timestamp.Local().Format("2006-01-02 15:04:05"), h.ChatJID, h.MsgID, h.SenderName, h.Snippet)
+			}
+			if res.NextPageToken != "" {
+				fmt.Fprintf(os.Stdout, "\n--page-token %s for more\n", res.NextPageToken)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&query, "query", "", "full-text search query (space-separated terms, AND-joined); supports sender:/chat:/text: scoped tokens")
+	cmd.Flags().StringVar(&chat, "chat", "", "restrict to chat JID")
+	cmd.Flags().StringVar(&sender, "sender", "", "restrict to sender JID")
+	cmd.Flags().StringVar(&since, "since", "", "only messages after this time (RFC3339 or 2006-01-02)")
+	cmd.Flags().StringVar(&until, "until", "", "only messages before this time (RFC3339 or 2006-01-02)")
+	cmd.Flags().BoolVar(&fromMe, "from-me", false, "only messages sent by the local user")
+	cmd.Flags().BoolVar(&notFromMe, "not-from-me", false, "only messages received from others")
+	cmd.Flags().IntVar(&limit, "limit", 50, "page size")
+	cmd.Flags().StringVar(&pageToken, "page-token", "", "resume from a previous next_page_token")
+	return cmd
+}
+
+func parseFlexTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02T15:04", s, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("unrecognized time format %q (want RFC3339, 2006-01-02T15:04, or 2006-01-02)", s)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/wacli/internal/config"
+	"github.com/steipete/wacli/internal/ipc"
+	"github.com/steipete/wacli/internal/out"
+	"github.com/steipete/wacli/internal/wa"
+)
+
+func newPresenceCmd(flags *rootFlags) *cobra.Command {
+	var online bool
+	var offline bool
+	var noIPC bool
+
+	cmd := &cobra.Command{
+		Use:   "presence",
+		Short: "Broadcast our global availability (online/offline)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if online == offline {
+				return fmt.Errorf("exactly one of --online or --offline is required")
+			}
+			available := online
+
+			storeDir := flags.storeDir
+			if storeDir == "" {
+				storeDir = config.DefaultStoreDir()
+			}
+			storeDir, _ = filepath.Abs(storeDir)
+
+			if !noIPC {
+				client := ipc.NewClient(storeDir)
+				if client.IsAvailable() {
+					if err := client.SetPresence(available); err != nil {
+						fmt.Fprintf(os.Stderr, "IPC presence set failed (%v), trying direct mode...\n", err)
+					} else {
+						if flags.asJSON {
+							return out.WriteJSON(os.Stdout, map[string]any{"available": available, "via": "ipc"})
+						}
+						fmt.Fprintln(os.Stdout, "Presence updated via daemon")
+						return nil
+					}
+				}
+			}
+
+			ctx, cancel := withTimeout(context.Background(), flags)
+			defer cancel()
+
+			a, lk, err := newApp(ctx, flags, true, false)
+			if err != nil {
+				return err
+			}
+			defer closeApp(a, lk)
+
+			if err := a.EnsureAuthed(); err != nil {
+				return err
+			}
+			if err := a.Connect(ctx, false, nil); err != nil {
+				return err
+			}
+
+			waClient, ok := a.WA().(*wa.Client)
+			if !ok {
+				return fmt.Errorf("unexpected WA client type")
+			}
+			if err := waClient.SetPresence(ctx, available); err != nil {
+				return err
+			}
+
+			if flags.asJSON {
+				return out.WriteJSON(os.Stdout, map[string]any{"available": available, "via": "direct"})
+			}
+			fmt.Fprintln(os.Stdout, "Presence updated")
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&online, "online", false, "broadcast as available")
+	cmd.Flags().BoolVar(&offline, "offline", false, "broadcast as unavailable")
+	cmd.Flags().BoolVar(&noIPC, "no-ipc", false, "skip IPC and use direct connection")
+	return cmd
+}
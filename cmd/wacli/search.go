@@ -0,0 +1,276 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/wacli/internal/out"
+	"github.com/steipete/wacli/internal/store"
+)
+
+func newSearchCmd(flags *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "search",
+		Short: "Save and re-run message search filters (smart folders)",
+	}
+	cmd.AddCommand(newSearchSaveCmd(flags))
+	cmd.AddCommand(newSearchRunCmd(flags))
+	cmd.AddCommand(newSearchListCmd(flags))
+	cmd.AddCommand(newSearchSaveChatsCmd(flags))
+	cmd.AddCommand(newSearchRunChatsCmd(flags))
+	return cmd
+}
+
+func newSearchSaveCmd(flags *rootFlags) *cobra.Command {
+	var query string
+	var chat string
+	var senders []string
+	var tags []string
+	var fromMe, notFromMe bool
+
+	cmd := &cobra.Command{
+		Use:   "save <name>",
+		Short: "Save a message filter as a named smart folder",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			ctx, cancel := withTimeout(context.Background(), flags)
+			defer cancel()
+
+			a, lk, err := newApp(ctx, flags, false, false)
+			if err != nil {
+				return err
+			}
+			defer closeApp(a, lk)
+
+			f := store.MessageFilter{}
+			if strings.TrimSpace(query) != "" {
+				terms := strings.Fields(query)
+				f.SearchStringFTS = &terms
+			}
+			if strings.TrimSpace(chat) != "" {
+				jids := []string{chat}
+				f.ChatJID = &jids
+			}
+			if len(senders) > 0 {
+				jids := append([]string(nil), senders...)
+				f.SenderJID = &jids
+			}
+			if len(tags) > 0 {
+				t := append([]string(nil), tags...)
+				f.SenderTags = &t
+			}
+			f.FromMe = boolFilter(fromMe, notFromMe)
+
+			if err := a.DB().SaveSearch(name, f); err != nil {
+				return err
+			}
+
+			if flags.asJSON {
+				return out.WriteJSON(os.Stdout, map[string]any{"saved": name})
+			}
+			fmt.Fprintf(os.Stdout, "Saved search %q\n", name)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&query, "query", "", "full-text search query")
+	cmd.Flags().StringVar(&chat, "chat", "", "restrict to chat JID")
+	cmd.Flags().StringArrayVar(&senders, "sender", nil, "restrict to sender JID (repeatable)")
+	cmd.Flags().StringArrayVar(&tags, "tag", nil, "restrict to senders carrying this contact tag (repeatable, matches any)")
+	cmd.Flags().BoolVar(&fromMe, "from-me", false, "only messages sent by the local user")
+	cmd.Flags().BoolVar(&notFromMe, "not-from-me", false, "only messages received from others")
+	return cmd
+}
+
+// newSearchSaveChatsCmd is search.save's chats-list counterpart: it persists
+// a ChatListFilter (the same criteria `chats list` filters on) as a named
+// smart folder, rather than a MessageFilter.
+func newSearchSaveChatsCmd(flags *rootFlags) *cobra.Command {
+	var query string
+	var archived, noArchived, pinned, noPinned, muted, noMuted, unread, noUnread bool
+	var tags []string
+	var hasTag, noTag bool
+	var labels []string
+	var hasLabel, noLabel bool
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "save-chats <name>",
+		Short: "Save a chats-list filter as a named smart folder",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			ctx, cancel := withTimeout(context.Background(), flags)
+			defer cancel()
+
+			a, lk, err := newApp(ctx, flags, false, false)
+			if err != nil {
+				return err
+			}
+			defer closeApp(a, lk)
+
+			f := store.ChatListFilter{Query: query, Limit: limit}
+			f.Archived = boolFilter(archived, noArchived)
+			f.Pinned = boolFilter(pinned, noPinned)
+			f.Muted = boolFilter(muted, noMuted)
+			f.Unread = boolFilter(unread, noUnread)
+			if len(tags) > 0 {
+				f.Tags = &tags
+			}
+			f.HasTag = boolFilter(hasTag, noTag)
+			if len(labels) > 0 {
+				f.Labels = &labels
+			}
+			f.HasLabel = boolFilter(hasLabel, noLabel)
+
+			if err := a.DB().SaveChatSearch(name, f); err != nil {
+				return err
+			}
+
+			if flags.asJSON {
+				return out.WriteJSON(os.Stdout, map[string]any{"saved": name})
+			}
+			fmt.Fprintf(os.Stdout, "Saved chats search %q\n", name)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&query, "query", "", "search query")
+	cmd.Flags().BoolVar(&archived, "archived", false, "show only archived chats")
+	cmd.Flags().BoolVar(&noArchived, "no-archived", false, "exclude archived chats")
+	cmd.Flags().BoolVar(&pinned, "pinned", false, "show only pinned chats")
+	cmd.Flags().BoolVar(&noPinned, "no-pinned", false, "exclude pinned chats")
+	cmd.Flags().BoolVar(&muted, "muted", false, "show only muted chats")
+	cmd.Flags().BoolVar(&noMuted, "no-muted", false, "exclude muted chats")
+	cmd.Flags().BoolVar(&unread, "unread", false, "show only unread chats")
+	cmd.Flags().BoolVar(&noUnread, "no-unread", false, "exclude unread chats")
+	cmd.Flags().StringArrayVar(&tags, "tag", nil, "show only chats with this contact tag (repeatable, matches any)")
+	cmd.Flags().BoolVar(&hasTag, "has-tag", false, "show only chats that have any tag")
+	cmd.Flags().BoolVar(&noTag, "no-tag", false, "show only chats that have no tags")
+	cmd.Flags().StringArrayVar(&labels, "label", nil, "show only chats with this label (repeatable, matches any)")
+	cmd.Flags().BoolVar(&hasLabel, "has-label", false, "show only chats that have any label")
+	cmd.Flags().BoolVar(&noLabel, "no-label", false, "show only chats that have no labels")
+	cmd.Flags().IntVar(&limit, "limit", 50, "limit")
+	return cmd
+}
+
+func newSearchRunChatsCmd(flags *rootFlags) *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "run-chats <name>",
+		Short: "Run a saved chats-list smart folder",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			ctx, cancel := withTimeout(context.Background(), flags)
+			defer cancel()
+
+			a, lk, err := newApp(ctx, flags, false, false)
+			if err != nil {
+				return err
+			}
+			defer closeApp(a, lk)
+
+			chats, err := a.DB().RunSavedChatSearch(name, limit)
+			if err != nil {
+				return err
+			}
+
+			if flags.asJSON {
+				return out.WriteJSON(os.Stdout, chats)
+			}
+			w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "KIND\tNAME\tJID\tLAST")
+			for _, c := range chats {
+				name := c.Name
+				if name == "" {
+					name = c.JID
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", c.Kind, truncate(name, 28), c.JID, c.LastMessageTS.Local().Format("2006-01-02 15:04:05"))
+			}
+			_ = w.Flush()
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&limit, "limit", 0, "override the saved filter's page size (0 = use saved limit)")
+	return cmd
+}
+
+func newSearchRunCmd(flags *rootFlags) *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "run <name>",
+		Short: "Run a saved smart folder",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			ctx, cancel := withTimeout(context.Background(), flags)
+			defer cancel()
+
+			a, lk, err := newApp(ctx, flags, false, false)
+			if err != nil {
+				return err
+			}
+			defer closeApp(a, lk)
+
+			res, err := a.DB().RunSavedSearch(ctx, name, limit)
+			if err != nil {
+				return err
+			}
+
+			if flags.asJSON {
+				return out.WriteJSON(os.Stdout, map[string]any{
+					"messages":        res.Messages,
+					"next_page_token": res.NextPageToken,
+				})
+			}
+			for _, m := range res.Messages {
+				fmt.Fprintf(os.Stdout, "[%s] %s (%s) %s: %s\n",
+					m.Timestamp.Local().Format("2006-01-02 15:04:05"), m.ChatJID, m.MsgID, m.SenderName, m.Text)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&limit, "limit", 50, "max messages to return")
+	return cmd
+}
+
+func newSearchListCmd(flags *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List saved smart folders",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := withTimeout(context.Background(), flags)
+			defer cancel()
+
+			a, lk, err := newApp(ctx, flags, false, false)
+			if err != nil {
+				return err
+			}
+			defer closeApp(a, lk)
+
+			searches, err := a.DB().ListSavedSearches()
+			if err != nil {
+				return err
+			}
+
+			if flags.asJSON {
+				return out.WriteJSON(os.Stdout, searches)
+			}
+			for _, s := range searches {
+				fmt.Fprintf(os.Stdout, "%s\t%s\t%s\n", s.Name, s.Kind, s.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+			}
+			return nil
+		},
+	}
+	return cmd
+}
@@ -22,6 +22,8 @@ func newSendCmd(flags *rootFlags) *cobra.Command {
 	}
 	cmd.AddCommand(newSendTextCmd(flags))
 	cmd.AddCommand(newSendFileCmd(flags))
+	cmd.AddCommand(newSendReactionCmd(flags))
+	cmd.AddCommand(newSendLocationCmd(flags))
 	return cmd
 }
 
@@ -102,7 +104,7 @@ func newSendTextCmd(flags *rootFlags) *cobra.Command {
 			chatName := a.WA().ResolveChatName(ctx, chat, "")
 			kind := chatKindFromJID(chat)
 			_ = a.DB().UpsertChat(chat.String(), kind, chatName, now)
-			_ = a.DB().UpsertMessage(store.UpsertMessageParams{
+			_ = a.RecordMessage(store.UpsertMessageParams{
 				ChatJID:    chat.String(),
 				ChatName:   chatName,
 				MsgID:      string(msgID),
@@ -131,3 +133,160 @@ func newSendTextCmd(flags *rootFlags) *cobra.Command {
 	cmd.Flags().BoolVar(&noIPC, "no-ipc", false, "skip IPC and use direct connection")
 	return cmd
 }
+
+func newSendReactionCmd(flags *rootFlags) *cobra.Command {
+	var chat string
+	var msgID string
+	var emoji string
+	var fromMe bool
+	var noIPC bool
+
+	cmd := &cobra.Command{
+		Use:   "reaction",
+		Short: "React to a message with an emoji (empty --emoji clears it)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if chat == "" || msgID == "" {
+				return fmt.Errorf("--chat and --msg-id are required")
+			}
+
+			storeDir := flags.storeDir
+			if storeDir == "" {
+				storeDir = config.DefaultStoreDir()
+			}
+			storeDir, _ = filepath.Abs(storeDir)
+
+			if !noIPC {
+				client := ipc.NewClient(storeDir)
+				if client.IsAvailable() {
+					if err := client.SendReaction(chat, msgID, emoji, fromMe); err != nil {
+						fmt.Fprintf(os.Stderr, "IPC reaction failed (%v), trying direct mode...\n", err)
+					} else {
+						if flags.asJSON {
+							return out.WriteJSON(os.Stdout, map[string]any{"reacted": true, "via": "ipc"})
+						}
+						fmt.Fprintln(os.Stdout, "Reaction sent via daemon")
+						return nil
+					}
+				}
+			}
+
+			ctx, cancel := withTimeout(context.Background(), flags)
+			defer cancel()
+
+			a, lk, err := newApp(ctx, flags, true, false)
+			if err != nil {
+				return err
+			}
+			defer closeApp(a, lk)
+
+			if err := a.EnsureAuthed(); err != nil {
+				return err
+			}
+			if err := a.Connect(ctx, false, nil); err != nil {
+				return err
+			}
+
+			chatJID, err := wa.ParseUserOrJID(chat)
+			if err != nil {
+				return err
+			}
+			waClient, ok := a.WA().(*wa.Client)
+			if !ok {
+				return fmt.Errorf("unexpected WA client type")
+			}
+			if err := waClient.SendReaction(ctx, chatJID, msgID, fromMe, emoji); err != nil {
+				return err
+			}
+
+			if flags.asJSON {
+				return out.WriteJSON(os.Stdout, map[string]any{"reacted": true, "via": "direct"})
+			}
+			fmt.Fprintln(os.Stdout, "Reaction sent")
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&chat, "chat", "", "chat JID the message belongs to")
+	cmd.Flags().StringVar(&msgID, "msg-id", "", "message id to react to")
+	cmd.Flags().StringVar(&emoji, "emoji", "", "reaction emoji (empty clears an existing reaction)")
+	cmd.Flags().BoolVar(&fromMe, "from-me", false, "react to a message we sent")
+	cmd.Flags().BoolVar(&noIPC, "no-ipc", false, "skip IPC and use direct connection")
+	return cmd
+}
+
+func newSendLocationCmd(flags *rootFlags) *cobra.Command {
+	var to string
+	var lat, lng float64
+	var name string
+	var noIPC bool
+
+	cmd := &cobra.Command{
+		Use:   "location",
+		Short: "Send a one-off location pin",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if to == "" {
+				return fmt.Errorf("--to is required")
+			}
+
+			storeDir := flags.storeDir
+			if storeDir == "" {
+				storeDir = config.DefaultStoreDir()
+			}
+			storeDir, _ = filepath.Abs(storeDir)
+
+			if !noIPC {
+				client := ipc.NewClient(storeDir)
+				if client.IsAvailable() {
+					if err := client.SendLocation(to, lat, lng, name); err != nil {
+						fmt.Fprintf(os.Stderr, "IPC location send failed (%v), trying direct mode...\n", err)
+					} else {
+						if flags.asJSON {
+							return out.WriteJSON(os.Stdout, map[string]any{"sent": true, "via": "ipc"})
+						}
+						fmt.Fprintln(os.Stdout, "Location sent via daemon")
+						return nil
+					}
+				}
+			}
+
+			ctx, cancel := withTimeout(context.Background(), flags)
+			defer cancel()
+
+			a, lk, err := newApp(ctx, flags, true, false)
+			if err != nil {
+				return err
+			}
+			defer closeApp(a, lk)
+
+			if err := a.EnsureAuthed(); err != nil {
+				return err
+			}
+			if err := a.Connect(ctx, false, nil); err != nil {
+				return err
+			}
+
+			toJID, err := wa.ParseUserOrJID(to)
+			if err != nil {
+				return err
+			}
+			waClient, ok := a.WA().(*wa.Client)
+			if !ok {
+				return fmt.Errorf("unexpected WA client type")
+			}
+			if err := waClient.SendLocation(ctx, toJID, lat, lng, name); err != nil {
+				return err
+			}
+
+			if flags.asJSON {
+				return out.WriteJSON(os.Stdout, map[string]any{"sent": true, "via": "direct"})
+			}
+			fmt.Fprintln(os.Stdout, "Location sent")
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&to, "to", "", "recipient phone number or JID")
+	cmd.Flags().Float64Var(&lat, "lat", 0, "latitude")
+	cmd.Flags().Float64Var(&lng, "lng", 0, "longitude")
+	cmd.Flags().StringVar(&name, "name", "", "optional place name")
+	cmd.Flags().BoolVar(&noIPC, "no-ipc", false, "skip IPC and use direct connection")
+	return cmd
+}
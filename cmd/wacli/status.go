@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/wacli/internal/config"
+	"github.com/steipete/wacli/internal/ipc"
+	"github.com/steipete/wacli/internal/out"
+)
+
+func newStatusCmd(flags *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Report the sync daemon's connection health",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			storeDir := flags.storeDir
+			if storeDir == "" {
+				storeDir = config.DefaultStoreDir()
+			}
+			storeDir, _ = filepath.Abs(storeDir)
+
+			client := ipc.NewClient(storeDir)
+			if !client.IsAvailable() {
+				if flags.asJSON {
+					return out.WriteJSON(os.Stdout, map[string]any{
+						"running": false,
+					})
+				}
+				fmt.Fprintln(os.Stdout, "sync daemon is not running")
+				return nil
+			}
+
+			st, err := client.Status()
+			if err != nil {
+				return fmt.Errorf("query status: %w", err)
+			}
+
+			if flags.asJSON {
+				return out.WriteJSON(os.Stdout, map[string]any{
+					"running": true,
+					"status":  st,
+				})
+			}
+
+			stale := ""
+			if st.Stale(time.Now()) {
+				stale = " (stale)"
+			}
+			fmt.Fprintf(os.Stdout, "%s%s\n", st.State, stale)
+			if st.Reason != "" {
+				fmt.Fprintf(os.Stdout, "reason: %s\n", st.Reason)
+			}
+			fmt.Fprintf(os.Stdout, "as of: %s\n", st.Timestamp.Local().Format(time.RFC3339))
+			return nil
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/steipete/wacli/internal/ipc"
+)
+
+// TestStatusReporter_ChangedDedupesConsecutivePosts is a regression test for
+// statusReporter.changed: posting the same (state, reason) twice in a row
+// must not hit --status-endpoint twice, since a no-op tracker.Set shouldn't
+// generate traffic, while an actual state change always must.
+func TestStatusReporter_ChangedDedupesConsecutivePosts(t *testing.T) {
+	var posts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&posts, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	r := newStatusReporter(srv.URL, "")
+
+	connected := ipc.Status{State: ipc.StateConnected, Reason: ""}
+	r.changed(connected)
+	r.changed(connected) // duplicate: must be suppressed
+	if got := atomic.LoadInt32(&posts); got != 1 {
+		t.Fatalf("expected 1 post after two identical changed() calls, got %d", got)
+	}
+
+	r.changed(ipc.Status{State: ipc.StateTransientDisconnect, Reason: "timeout"})
+	if got := atomic.LoadInt32(&posts); got != 2 {
+		t.Fatalf("expected 2 posts after a genuine state change, got %d", got)
+	}
+
+	r.changed(ipc.Status{State: ipc.StateTransientDisconnect, Reason: "timeout"}) // duplicate again
+	if got := atomic.LoadInt32(&posts); got != 2 {
+		t.Fatalf("expected the repeated state+reason to stay deduped, got %d posts", got)
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steipete/wacli/internal/store"
+)
+
+// migrateTables lists the store tables in dependency order (chats/contacts/
+// groups before the rows that reference them) along with the column each
+// table conflicts on for the upsert written to the destination.
+var migrateTables = []struct {
+	name         string
+	conflictCols []string
+}{
+	{"chats", []string{"jid"}},
+	{"contacts", []string{"jid"}},
+	{"groups", []string{"jid"}},
+	{"group_participants", []string{"group_jid", "user_jid"}},
+	{"contact_aliases", []string{"jid"}},
+	{"contact_tags", []string{"jid", "tag"}},
+	{"chat_labels", []string{"jid", "label"}},
+	{"messages", []string{"chat_jid", "msg_id"}},
+}
+
+func newStoreCmd(flags *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "store",
+		Short: "Manage the wacli message store",
+	}
+	cmd.AddCommand(newStoreMigrateCmd(flags))
+	return cmd
+}
+
+func newStoreMigrateCmd(flags *rootFlags) *cobra.Command {
+	var from, to string
+	var batchSize int
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Stream chats/contacts/groups/messages between store backends",
+		Long:  "Copies the wacli store tables from one backend DSN to another, e.g.\n  wacli store migrate --from sqlite:./wacli.db --to postgres://user@host/wacli\nThis lets multi-daemon deployments move off a single host's storeDir onto a shared database.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if from == "" || to == "" {
+				return fmt.Errorf("--from and --to are required")
+			}
+
+			srcDB, _, err := store.OpenRaw(from)
+			if err != nil {
+				return fmt.Errorf("open --from: %w", err)
+			}
+			defer srcDB.Close()
+
+			dstDB, dstDriver, err := store.OpenRaw(to)
+			if err != nil {
+				return fmt.Errorf("open --to: %w", err)
+			}
+			defer dstDB.Close()
+
+			if err := store.EnsureSchemaFor(dstDB, dstDriver); err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			total := 0
+			for _, tbl := range migrateTables {
+				n, err := migrateTable(ctx, srcDB, dstDB, dstDriver, tbl.name, tbl.conflictCols, batchSize)
+				if err != nil {
+					return fmt.Errorf("migrate table %s: %w", tbl.name, err)
+				}
+				fmt.Fprintf(os.Stdout, "%s: copied %d rows\n", tbl.name, n)
+				total += n
+			}
+			fmt.Fprintf(os.Stdout, "done: %d rows total\n", total)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&from, "from", "", "source DSN, e.g. sqlite:./wacli.db")
+	cmd.Flags().StringVar(&to, "to", "", "destination DSN, e.g. postgres://user@host/wacli")
+	cmd.Flags().IntVar(&batchSize, "batch-size", 500, "rows per destination transaction")
+	return cmd
+}
+
+// migrateTable streams every row of table from src to dst in batches of
+// batchSize, rows per destination transaction, preserving column values
+// (including rowid-derived ordering for messages, so FTS stays consistent
+// once re-indexed on the destination).
+func migrateTable(ctx context.Context, src, dst *sql.DB, dstDriver store.Driver, table string, conflictCols []string, batchSize int) (int, error) {
+	rows, err := src.QueryContext(ctx, "SELECT * FROM "+table)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+
+	assignments := make([]string, 0, len(cols))
+	for _, c := range cols {
+		assignments = append(assignments, fmt.Sprintf("%s=%s", c, dstDriver.Excluded(c)))
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(cols)), ",")
+	insertSQL := dstDriver.Rebind(fmt.Sprintf(
+		"INSERT INTO %s(%s) VALUES(%s) %s",
+		table, strings.Join(cols, ","), placeholders, dstDriver.UpsertClause(conflictCols, assignments),
+	))
+
+	tx, err := dst.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	stmt, err := tx.PrepareContext(ctx, insertSQL)
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+
+	n := 0
+	inBatch := 0
+	vals := make([]interface{}, len(cols))
+	scanDest := make([]interface{}, len(cols))
+	for i := range vals {
+		scanDest[i] = &vals[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(scanDest...); err != nil {
+			_ = stmt.Close()
+			_ = tx.Rollback()
+			return n, err
+		}
+		if _, err := stmt.ExecContext(ctx, vals...); err != nil {
+			_ = stmt.Close()
+			_ = tx.Rollback()
+			return n, err
+		}
+		n++
+		inBatch++
+		if inBatch >= batchSize {
+			if err := stmt.Close(); err != nil {
+				_ = tx.Rollback()
+				return n, err
+			}
+			if err := tx.Commit(); err != nil {
+				return n, err
+			}
+			tx, err = dst.BeginTx(ctx, nil)
+			if err != nil {
+				return n, err
+			}
+			stmt, err = tx.PrepareContext(ctx, insertSQL)
+			if err != nil {
+				_ = tx.Rollback()
+				return n, err
+			}
+			inBatch = 0
+		}
+	}
+	if err := rows.Err(); err != nil {
+		_ = stmt.Close()
+		_ = tx.Rollback()
+		return n, err
+	}
+
+	if err := stmt.Close(); err != nil {
+		_ = tx.Rollback()
+		return n, err
+	}
+	if err := tx.Commit(); err != nil {
+		return n, err
+	}
+	return n, nil
+}
@@ -1,10 +1,17 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -12,13 +19,48 @@ import (
 	appPkg "github.com/steipete/wacli/internal/app"
 	"github.com/steipete/wacli/internal/ipc"
 	"github.com/steipete/wacli/internal/out"
+	"github.com/steipete/wacli/internal/provisioning"
 	"github.com/steipete/wacli/internal/store"
 	"github.com/steipete/wacli/internal/wa"
 )
 
 // syncHandler implements ipc.Handler for the sync daemon.
 type syncHandler struct {
-	app *appPkg.App
+	app        *appPkg.App
+	tracker    *ipc.StatusTracker
+	events     *ipc.EventBus
+	supervisor *appPkg.KeepAliveSupervisor
+}
+
+// publish delivers evt to IPC "subscribe" connections, if any are enabled.
+func (h *syncHandler) publish(evt ipc.Event) {
+	if h.events != nil {
+		h.events.Publish(evt)
+	}
+}
+
+func (h *syncHandler) Status() (ipc.Status, error) {
+	if h.tracker == nil {
+		return ipc.Status{State: ipc.StateUnknownError, Timestamp: time.Now(), Reason: "status tracking not enabled"}, nil
+	}
+	st := h.tracker.Get()
+	if h.supervisor != nil {
+		ks := h.supervisor.State()
+		if st.Info == nil {
+			st.Info = make(map[string]string, 4)
+		}
+		st.Info["supervisor_connected"] = strconv.FormatBool(ks.Connected)
+		st.Info["supervisor_consecutive_failures"] = strconv.Itoa(ks.ConsecutiveFailures)
+		st.Info["supervisor_retries"] = strconv.Itoa(ks.Retries)
+		if !ks.LastFailure.IsZero() {
+			st.Info["supervisor_last_failure"] = ks.LastFailure.Format(time.RFC3339)
+			st.Info["supervisor_last_failure_reason"] = ks.LastFailureReason
+		}
+		if !ks.NextAttempt.IsZero() {
+			st.Info["supervisor_next_attempt"] = ks.NextAttempt.Format(time.RFC3339)
+		}
+	}
+	return st, nil
 }
 
 func (h *syncHandler) SendText(to, message string) (string, error) {
@@ -31,27 +73,27 @@ func (h *syncHandler) SendText(to, message string) (string, error) {
 	if !h.app.WA().IsConnected() {
 		return "", fmt.Errorf("whatsapp not connected")
 	}
-	
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
-	
+
 	toJID, err := wa.ParseUserOrJID(to)
 	if err != nil {
 		return "", fmt.Errorf("parse recipient: %w", err)
 	}
-	
+
 	msgID, err := h.app.WA().SendText(ctx, toJID, message)
 	if err != nil {
 		return "", fmt.Errorf("send: %w", err)
 	}
-	
+
 	// Store the message in the local DB
 	now := time.Now().UTC()
 	chat := toJID
 	chatName := h.app.WA().ResolveChatName(ctx, chat, "")
 	kind := chatKindFromJID(chat)
 	_ = h.app.DB().UpsertChat(chat.String(), kind, chatName, now)
-	_ = h.app.DB().UpsertMessage(store.UpsertMessageParams{
+	_ = h.app.RecordMessage(store.UpsertMessageParams{
 		ChatJID:    chat.String(),
 		ChatName:   chatName,
 		MsgID:      string(msgID),
@@ -61,7 +103,10 @@ func (h *syncHandler) SendText(to, message string) (string, error) {
 		FromMe:     true,
 		Text:       message,
 	})
-	
+	h.publish(ipc.Event{Type: "message", Timestamp: now, ChatJID: chat.String(), Data: map[string]any{
+		"msg_id": string(msgID), "from_me": true, "text": message,
+	}})
+
 	return string(msgID), nil
 }
 
@@ -75,25 +120,268 @@ func (h *syncHandler) DeleteMessage(chat, msgID string, forEveryone bool) error
 	if !h.app.WA().IsConnected() {
 		return fmt.Errorf("whatsapp not connected")
 	}
-	
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
-	
+
 	chatJID, err := wa.ParseUserOrJID(chat)
 	if err != nil {
 		return fmt.Errorf("parse chat: %w", err)
 	}
-	
+
 	// Type assert to get the concrete client
 	waClient, ok := h.app.WA().(*wa.Client)
 	if !ok {
 		return fmt.Errorf("unexpected WA client type")
 	}
-	
-	return waClient.RevokeMessage(ctx, chatJID, msgID, forEveryone)
+
+	if err := waClient.RevokeMessage(ctx, chatJID, msgID, forEveryone); err != nil {
+		return err
+	}
+	h.publish(ipc.Event{Type: "message_revoke", Timestamp: time.Now(), ChatJID: chat, Data: map[string]any{
+		"msg_id": msgID, "for_everyone": forEveryone,
+	}})
+	return nil
+}
+
+func (h *syncHandler) SendFile(to, path, caption, mimetype string) (string, error) {
+	if h.app == nil {
+		return "", fmt.Errorf("app not initialized")
+	}
+	if h.app.WA() == nil {
+		return "", fmt.Errorf("whatsapp client not initialized")
+	}
+	if !h.app.WA().IsConnected() {
+		return "", fmt.Errorf("whatsapp not connected")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	toJID, err := wa.ParseUserOrJID(to)
+	if err != nil {
+		return "", fmt.Errorf("parse recipient: %w", err)
+	}
+
+	waClient, ok := h.app.WA().(*wa.Client)
+	if !ok {
+		return "", fmt.Errorf("unexpected WA client type")
+	}
+
+	msgID, err := waClient.SendFile(ctx, toJID, path, caption, mimetype)
+	if err != nil {
+		return "", fmt.Errorf("send file: %w", err)
+	}
+
+	now := time.Now().UTC()
+	chatName := h.app.WA().ResolveChatName(ctx, toJID, "")
+	kind := chatKindFromJID(toJID)
+	_ = h.app.DB().UpsertChat(toJID.String(), kind, chatName, now)
+	_ = h.app.RecordMessage(store.UpsertMessageParams{
+		ChatJID:    toJID.String(),
+		ChatName:   chatName,
+		MsgID:      string(msgID),
+		SenderJID:  "",
+		SenderName: "me",
+		Timestamp:  now,
+		FromMe:     true,
+		Text:       caption,
+	})
+	h.publish(ipc.Event{Type: "message", Timestamp: now, ChatJID: toJID.String(), Data: map[string]any{
+		"msg_id": string(msgID), "from_me": true, "file": path,
+	}})
+
+	return string(msgID), nil
+}
+
+func (h *syncHandler) SendReaction(chat, msgID, emoji string, fromMe bool) error {
+	if h.app == nil {
+		return fmt.Errorf("app not initialized")
+	}
+	if h.app.WA() == nil {
+		return fmt.Errorf("whatsapp client not initialized")
+	}
+	if !h.app.WA().IsConnected() {
+		return fmt.Errorf("whatsapp not connected")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	chatJID, err := wa.ParseUserOrJID(chat)
+	if err != nil {
+		return fmt.Errorf("parse chat: %w", err)
+	}
+
+	waClient, ok := h.app.WA().(*wa.Client)
+	if !ok {
+		return fmt.Errorf("unexpected WA client type")
+	}
+
+	if err := waClient.SendReaction(ctx, chatJID, msgID, fromMe, emoji); err != nil {
+		return err
+	}
+	h.publish(ipc.Event{Type: "reaction", Timestamp: time.Now(), ChatJID: chat, Data: map[string]any{
+		"msg_id": msgID, "emoji": emoji,
+	}})
+	return nil
+}
+
+func (h *syncHandler) SendLocation(to string, lat, lng float64, name string) error {
+	if h.app == nil {
+		return fmt.Errorf("app not initialized")
+	}
+	if h.app.WA() == nil {
+		return fmt.Errorf("whatsapp client not initialized")
+	}
+	if !h.app.WA().IsConnected() {
+		return fmt.Errorf("whatsapp not connected")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	toJID, err := wa.ParseUserOrJID(to)
+	if err != nil {
+		return fmt.Errorf("parse recipient: %w", err)
+	}
+
+	waClient, ok := h.app.WA().(*wa.Client)
+	if !ok {
+		return fmt.Errorf("unexpected WA client type")
+	}
+
+	if err := waClient.SendLocation(ctx, toJID, lat, lng, name); err != nil {
+		return err
+	}
+	h.publish(ipc.Event{Type: "message", Timestamp: time.Now(), ChatJID: toJID.String(), Data: map[string]any{
+		"from_me": true, "location": map[string]any{"lat": lat, "lng": lng, "name": name},
+	}})
+	return nil
+}
+
+func (h *syncHandler) SetPresence(available bool) error {
+	if h.app == nil {
+		return fmt.Errorf("app not initialized")
+	}
+	if h.app.WA() == nil {
+		return fmt.Errorf("whatsapp client not initialized")
+	}
+	if !h.app.WA().IsConnected() {
+		return fmt.Errorf("whatsapp not connected")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	waClient, ok := h.app.WA().(*wa.Client)
+	if !ok {
+		return fmt.Errorf("unexpected WA client type")
+	}
+
+	if err := waClient.SetPresence(ctx, available); err != nil {
+		return err
+	}
+	h.publish(ipc.Event{Type: "presence", Timestamp: time.Now(), Data: map[string]any{"available": available}})
+	return nil
+}
+
+func (h *syncHandler) SearchMessages(query, chat, sender, since, until string, fromMe *bool, limit int, pageToken string) (ipc.SearchMessagesResult, error) {
+	if h.app == nil {
+		return ipc.SearchMessagesResult{}, fmt.Errorf("app not initialized")
+	}
+
+	f := store.MessageFilter{FromMe: fromMe}
+	if strings.TrimSpace(query) != "" {
+		terms := strings.Fields(query)
+		f.SearchStringFTS = &terms
+	}
+	if strings.TrimSpace(chat) != "" {
+		jids := []string{chat}
+		f.ChatJID = &jids
+	}
+	if strings.TrimSpace(sender) != "" {
+		jids := []string{sender}
+		f.SenderJID = &jids
+	}
+	if since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return ipc.SearchMessagesResult{}, fmt.Errorf("invalid since: %w", err)
+		}
+		f.TimestampAfter = &t
+	}
+	if until != "" {
+		t, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			return ipc.SearchMessagesResult{}, fmt.Errorf("invalid until: %w", err)
+		}
+		f.TimestampBefore = &t
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	res, err := h.app.DB().ListMessages(ctx, f, limit, pageToken)
+	if err != nil {
+		return ipc.SearchMessagesResult{}, err
+	}
+
+	hits := make([]ipc.MessageHit, 0, len(res.Messages))
+	for _, m := range res.Messages {
+		hits = append(hits, ipc.MessageHit{
+			ChatJID:    m.ChatJID,
+			MsgID:      m.MsgID,
+			SenderName: m.SenderName,
+			Timestamp:  m.Timestamp.Unix(),
+			FromMe:     m.FromMe,
+			Snippet:    m.Text,
+		})
+	}
+	return ipc.SearchMessagesResult{Messages: hits, NextPageToken: res.NextPageToken, PrevPageToken: res.PrevPageToken}, nil
+}
+
+func (h *syncHandler) History(jid, verb, anchor, anchorEnd string, limit int) (ipc.HistoryResult, error) {
+	if h.app == nil {
+		return ipc.HistoryResult{}, fmt.Errorf("app not initialized")
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+
+	var msgs []store.Message
+	var err error
+	switch strings.ToUpper(verb) {
+	case "BEFORE":
+		msgs, err = h.app.HistoryBefore(jid, store.ParseHistoryAnchor(anchor), limit)
+	case "AFTER":
+		msgs, err = h.app.HistoryAfter(jid, store.ParseHistoryAnchor(anchor), limit)
+	case "LATEST":
+		msgs, err = h.app.HistoryLatest(jid, limit)
+	case "AROUND":
+		msgs, err = h.app.HistoryAround(jid, store.ParseHistoryAnchor(anchor), limit)
+	case "BETWEEN":
+		start := store.ParseHistoryAnchor(anchor).Timestamp
+		end := store.ParseHistoryAnchor(anchorEnd).Timestamp
+		msgs, err = h.app.HistoryBetween(jid, start, end, limit)
+	default:
+		return ipc.HistoryResult{}, fmt.Errorf("unknown history verb: %s", verb)
+	}
+	if err != nil {
+		return ipc.HistoryResult{}, err
+	}
+	return ipc.HistoryResult{Messages: msgs}, nil
 }
 
 func (h *syncHandler) ChatState(jid, action, duration string) error {
+	err := h.chatState(jid, action, duration)
+	if err == nil {
+		h.publish(ipc.Event{Type: "chat_state", Timestamp: time.Now(), ChatJID: jid, Data: map[string]string{"action": action}})
+	}
+	return err
+}
+
+func (h *syncHandler) chatState(jid, action, duration string) error {
 	if h.app == nil {
 		return fmt.Errorf("app not initialized")
 	}
@@ -149,21 +437,38 @@ func newSyncCmd(flags *rootFlags) *cobra.Command {
 	var refreshContacts bool
 	var refreshGroups bool
 	var enableIPC bool
+	var statusEndpoint string
+	var statusToken string
+	var httpListen string
+	var httpToken string
 
 	cmd := &cobra.Command{
 		Use:   "sync",
 		Short: "Sync messages (requires prior auth; never shows QR)",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if httpListen != "" && httpToken == "" && !isLoopbackAddr(httpListen) {
+				return fmt.Errorf("--http-token is required when --http-listen binds a non-loopback address")
+			}
+
 			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 			defer stop()
 
+			tracker := ipc.NewStatusTracker()
+			if statusEndpoint != "" {
+				reporter := newStatusReporter(statusEndpoint, statusToken)
+				tracker.OnChange(reporter.changed)
+				go reporter.heartbeat(ctx, tracker)
+			}
+
 			a, lk, err := newApp(ctx, flags, true, false)
 			if err != nil {
 				return err
 			}
 			defer closeApp(a, lk)
 
+			tracker.Set(ipc.StateConnecting, "", nil)
 			if err := a.EnsureAuthed(); err != nil {
+				tracker.Set(ipc.StateBadCredentials, err.Error(), nil)
 				return err
 			}
 
@@ -178,9 +483,25 @@ func newSyncCmd(flags *rootFlags) *cobra.Command {
 
 			// Start IPC server if enabled (default for --follow mode)
 			var ipcServer *ipc.Server
-			if enableIPC && mode == appPkg.SyncModeFollow {
-				handler := &syncHandler{app: a}
+			var handler *syncHandler
+			if mode == appPkg.SyncModeFollow {
+				handler = &syncHandler{app: a, tracker: tracker}
+				if waClient, ok := a.WA().(*wa.Client); ok {
+					waClient.OnEvent(func(evt wa.IncomingEvent) {
+						handler.publish(ipc.Event{Type: evt.Type, Timestamp: time.Now(), ChatJID: evt.ChatJID, Data: evt.Data})
+					})
+				}
+			}
+			if enableIPC && handler != nil {
 				ipcServer = ipc.NewServer(a.StoreDir(), handler)
+				handler.events = ipcServer.Events()
+				tracker.OnChange(func(st ipc.Status) {
+					evtType := "connect"
+					if st.State != ipc.StateConnected {
+						evtType = "disconnect"
+					}
+					handler.publish(ipc.Event{Type: evtType, Timestamp: time.Now(), Data: st})
+				})
 				if err := ipcServer.Start(); err != nil {
 					fmt.Fprintf(os.Stderr, "Warning: failed to start IPC server: %v\n", err)
 				} else {
@@ -188,6 +509,54 @@ func newSyncCmd(flags *rootFlags) *cobra.Command {
 				}
 			}
 
+			// Start the HTTP/WebSocket provisioning API if requested
+			// (non-Go clients: mobile apps, browser dashboards, home automation).
+			var httpServer *provisioning.Server
+			if httpListen != "" && handler != nil {
+				bus := provisioning.NewEventBus()
+				tracker.OnChange(func(st ipc.Status) {
+					bus.Publish(provisioning.Event{Type: "connection_state", Timestamp: time.Now(), Data: st})
+				})
+				if waClient, ok := a.WA().(*wa.Client); ok {
+					waClient.OnEvent(func(evt wa.IncomingEvent) {
+						bus.Publish(provisioning.Event{Type: evt.Type, Timestamp: time.Now(), Data: evt.Data})
+					})
+				}
+				httpServer = provisioning.NewServer(httpListen, httpToken, handler, bus)
+				if err := httpServer.Start(); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: failed to start provisioning API: %v\n", err)
+					httpServer = nil
+				} else {
+					defer func() {
+						shutCtx, shutCancel := context.WithTimeout(context.Background(), 5*time.Second)
+						defer shutCancel()
+						_ = httpServer.Stop(shutCtx)
+					}()
+				}
+			}
+
+			if mode == appPkg.SyncModeFollow {
+				sweeper := appPkg.NewMuteSweeper(a, 30*time.Second)
+				go sweeper.Run(ctx, func(jid string) {
+					fmt.Fprintf(os.Stdout, "unmute: %s (mute expired)\n", jid)
+				})
+
+				supervisor := appPkg.NewKeepAliveSupervisor(a, 10*time.Second)
+				if handler != nil {
+					handler.supervisor = supervisor
+				}
+				go supervisor.Run(ctx, func(attempt int, err error) {
+					if err != nil {
+						fmt.Fprintf(os.Stderr, "reconnect attempt %d failed: %v\n", attempt, err)
+						tracker.Set(ipc.StateTransientDisconnect, err.Error(), nil)
+					} else {
+						fmt.Fprintf(os.Stdout, "reconnected (attempt %d)\n", attempt)
+						tracker.Set(ipc.StateConnected, "", nil)
+					}
+				})
+			}
+
+			tracker.Set(ipc.StateConnected, "", nil)
 			res, err := a.Sync(ctx, appPkg.SyncOptions{
 				Mode:            mode,
 				AllowQR:         false,
@@ -197,6 +566,7 @@ func newSyncCmd(flags *rootFlags) *cobra.Command {
 				IdleExit:        idleExit,
 			})
 			if err != nil {
+				tracker.Set(ipc.StateUnknownError, err.Error(), nil)
 				return err
 			}
 
@@ -218,5 +588,104 @@ func newSyncCmd(flags *rootFlags) *cobra.Command {
 	cmd.Flags().BoolVar(&refreshContacts, "refresh-contacts", false, "refresh contacts from session store into local DB")
 	cmd.Flags().BoolVar(&refreshGroups, "refresh-groups", false, "refresh joined groups (live) into local DB")
 	cmd.Flags().BoolVar(&enableIPC, "enable-ipc", true, "enable IPC socket for send commands (--follow mode only)")
+	cmd.Flags().StringVar(&statusEndpoint, "status-endpoint", "", "POST health state changes to this URL as JSON")
+	cmd.Flags().StringVar(&statusToken, "status-token", "", "bearer token for --status-endpoint")
+	cmd.Flags().StringVar(&httpListen, "http-listen", "", "address to serve the HTTP/WebSocket provisioning API on, e.g. :8080 (--follow mode only)")
+	cmd.Flags().StringVar(&httpToken, "http-token", "", "bearer token required by the provisioning API (required unless --http-listen is loopback-only)")
 	return cmd
 }
+
+// isLoopbackAddr reports whether addr (a "host:port" --http-listen value)
+// only binds the loopback interface, the one case where serving the
+// provisioning API without --http-token is safe. An addr that fails to
+// parse, or whose host is empty (binds all interfaces), is treated as
+// non-loopback; otherwise the host must be "localhost" or a loopback IP
+// literal.
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil || host == "" {
+		return false
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return strings.EqualFold(host, "localhost")
+	}
+	return ip.IsLoopback()
+}
+
+// statusReporter posts to --status-endpoint on state change, deduplicating
+// consecutive posts of the same (state, reason) so a no-op tracker.Set
+// doesn't re-send, and separately reposts the current status every TTL/5 as
+// a liveness heartbeat regardless of whether it changed.
+type statusReporter struct {
+	endpoint, token string
+
+	mu       sync.Mutex
+	lastSent string
+}
+
+func newStatusReporter(endpoint, token string) *statusReporter {
+	return &statusReporter{endpoint: endpoint, token: token}
+}
+
+func (r *statusReporter) changed(st ipc.Status) {
+	key := string(st.State) + "|" + st.Reason
+	r.mu.Lock()
+	dup := key == r.lastSent
+	r.lastSent = key
+	r.mu.Unlock()
+	if dup {
+		return
+	}
+	postStatus(r.endpoint, r.token, st)
+}
+
+// heartbeat reposts tracker's current status every TTL/5 (falling back to
+// the default 2-minute TTL's /5 if the status has none yet), so a consumer
+// polling for staleness always hears from a healthy daemon well within TTL.
+func (r *statusReporter) heartbeat(ctx context.Context, tracker *ipc.StatusTracker) {
+	interval := tracker.Get().TTL / 5
+	if interval <= 0 {
+		interval = 2 * time.Minute / 5
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			st := tracker.Get()
+			r.mu.Lock()
+			r.lastSent = string(st.State) + "|" + st.Reason
+			r.mu.Unlock()
+			postStatus(r.endpoint, r.token, st)
+		}
+	}
+}
+
+// postStatus POSTs st as JSON to endpoint, with token as a bearer
+// Authorization header if set. Failures are logged and otherwise ignored: a down
+// status-reporting endpoint must never block or crash the sync daemon.
+func postStatus(endpoint, token string, st ipc.Status) {
+	body, err := json.Marshal(st)
+	if err != nil {
+		return
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "status-endpoint: build request: %v\n", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "status-endpoint: %v\n", err)
+		return
+	}
+	_ = resp.Body.Close()
+}
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"time"
+
+	"github.com/steipete/wacli/internal/store"
+)
+
+// HistoryBefore returns up to limit messages in jid strictly older than
+// anchor, newest-first.
+func (a *App) HistoryBefore(jid string, anchor store.HistoryAnchor, limit int) ([]store.Message, error) {
+	return a.db.GetMessagesBefore(jid, anchor, limit)
+}
+
+// HistoryAfter returns up to limit messages in jid strictly newer than
+// anchor, oldest-first.
+func (a *App) HistoryAfter(jid string, anchor store.HistoryAnchor, limit int) ([]store.Message, error) {
+	return a.db.GetMessagesAfter(jid, anchor, limit)
+}
+
+// HistoryLatest returns the most recent limit messages in jid, newest-first.
+func (a *App) HistoryLatest(jid string, limit int) ([]store.Message, error) {
+	return a.db.GetLatestMessages(jid, limit)
+}
+
+// HistoryAround returns messages surrounding anchor in jid, oldest-first.
+func (a *App) HistoryAround(jid string, anchor store.HistoryAnchor, limit int) ([]store.Message, error) {
+	return a.db.GetMessagesAround(jid, anchor, limit)
+}
+
+// HistoryBetween returns messages in jid with timestamps in [start, end],
+// oldest-first.
+func (a *App) HistoryBetween(jid string, start, end time.Time, limit int) ([]store.Message, error) {
+	return a.db.GetMessagesBetween(jid, start, end, limit)
+}
+
+// ChatHistoryBefore returns a cursor-paginated page of jid strictly older
+// than cursor (or the newest page if cursor is empty), newest-first.
+func (a *App) ChatHistoryBefore(jid, cursor string, limit int) (store.ChatHistoryPage, error) {
+	return a.db.ChatHistoryBefore(jid, cursor, limit)
+}
+
+// ChatHistoryAfter returns a cursor-paginated page of jid strictly newer
+// than cursor, oldest-first.
+func (a *App) ChatHistoryAfter(jid, cursor string, limit int) (store.ChatHistoryPage, error) {
+	return a.db.ChatHistoryAfter(jid, cursor, limit)
+}
+
+// ChatHistoryLatest returns the most recent cursor-paginated page of jid.
+func (a *App) ChatHistoryLatest(jid string, limit int) (store.ChatHistoryPage, error) {
+	return a.db.ChatHistoryLatest(jid, limit)
+}
+
+// ChatHistoryAround returns a cursor-paginated page surrounding cursor in
+// jid, oldest-first.
+func (a *App) ChatHistoryAround(jid, cursor string, limit int) (store.ChatHistoryPage, error) {
+	return a.db.ChatHistoryAround(jid, cursor, limit)
+}
+
+// ChatHistoryBetween returns a cursor-paginated page of jid with
+// timestamps in [start, end], oldest-first.
+func (a *App) ChatHistoryBetween(jid string, start, end time.Time, limit int) (store.ChatHistoryPage, error) {
+	return a.db.ChatHistoryBetween(jid, start, end, limit)
+}
@@ -0,0 +1,152 @@
+package app
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/steipete/wacli/internal/wa"
+)
+
+const (
+	keepAliveFailureThreshold = 3
+	keepAliveMinBackoff       = 5 * time.Second
+	keepAliveMaxBackoff       = 5 * time.Minute
+	presenceResubscribeEvery  = 12 * time.Hour
+)
+
+// KeepAliveState is a snapshot of the supervisor's reconnect bookkeeping,
+// exposed so callers (the `status` IPC command) can render connection
+// health without reaching into the supervisor's internals.
+type KeepAliveState struct {
+	Connected            bool
+	ConsecutiveFailures  int
+	LastFailure          time.Time
+	LastFailureReason    string
+	Retries              int
+	NextAttempt          time.Time
+}
+
+// KeepAliveSupervisor watches the WhatsApp connection and reconnects with
+// jittered exponential backoff once consecutive keep-alive failures exceed
+// keepAliveFailureThreshold, the way slidge-whatsapp's connection manager
+// does. It also periodically re-subscribes to presence so WhatsApp keeps
+// pushing presence/typing updates for tracked chats.
+type KeepAliveSupervisor struct {
+	app           *App
+	checkInterval time.Duration
+
+	mu    sync.Mutex
+	state KeepAliveState
+}
+
+// NewKeepAliveSupervisor returns a supervisor that checks connection health
+// every checkInterval.
+func NewKeepAliveSupervisor(a *App, checkInterval time.Duration) *KeepAliveSupervisor {
+	return &KeepAliveSupervisor{app: a, checkInterval: checkInterval}
+}
+
+// State returns a snapshot of the supervisor's current bookkeeping.
+func (s *KeepAliveSupervisor) State() KeepAliveState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
+// Run blocks, periodically checking connection health and presence
+// freshness until ctx is canceled. onReconnect, if non-nil, is called after
+// every reconnect attempt with the attempt number (since the last success)
+// and its result.
+func (s *KeepAliveSupervisor) Run(ctx context.Context, onReconnect func(attempt int, err error)) {
+	ticker := time.NewTicker(s.checkInterval)
+	defer ticker.Stop()
+
+	nextPresence := time.Now().Add(jitter(presenceResubscribeEvery, 0.1))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.check(ctx, onReconnect)
+			if time.Now().After(nextPresence) {
+				s.resubscribePresence(ctx)
+				nextPresence = time.Now().Add(jitter(presenceResubscribeEvery, 0.1))
+			}
+		}
+	}
+}
+
+func (s *KeepAliveSupervisor) check(ctx context.Context, onReconnect func(int, error)) {
+	connected := s.app.WA() != nil && s.app.WA().IsConnected()
+
+	s.mu.Lock()
+	s.state.Connected = connected
+	if connected {
+		s.state.ConsecutiveFailures = 0
+		s.mu.Unlock()
+		return
+	}
+	s.state.ConsecutiveFailures++
+	failures := s.state.ConsecutiveFailures
+	s.state.LastFailure = time.Now()
+	retry := s.state.Retries
+	s.mu.Unlock()
+
+	if failures <= keepAliveFailureThreshold {
+		return
+	}
+
+	backoff := backoffFor(retry)
+	s.mu.Lock()
+	s.state.NextAttempt = time.Now().Add(backoff)
+	s.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return
+	case <-time.After(backoff):
+	}
+
+	if waClient, ok := s.app.WA().(*wa.Client); ok {
+		_ = waClient.Disconnect()
+	}
+	err := s.app.Connect(ctx, false, nil)
+
+	s.mu.Lock()
+	if err == nil {
+		s.state.ConsecutiveFailures = 0
+		s.state.Retries = 0
+		s.state.Connected = true
+	} else {
+		s.state.Retries++
+		s.state.LastFailureReason = err.Error()
+	}
+	s.mu.Unlock()
+
+	if onReconnect != nil {
+		onReconnect(retry+1, err)
+	}
+}
+
+func (s *KeepAliveSupervisor) resubscribePresence(ctx context.Context) {
+	waClient, ok := s.app.WA().(*wa.Client)
+	if !ok || !waClient.IsConnected() {
+		return
+	}
+	_ = waClient.SetPresence(ctx, true)
+}
+
+func backoffFor(retry int) time.Duration {
+	d := keepAliveMinBackoff * time.Duration(int64(1)<<uint(retry))
+	if d > keepAliveMaxBackoff || d <= 0 {
+		d = keepAliveMaxBackoff
+	}
+	return jitter(d, 0.2)
+}
+
+func jitter(d time.Duration, frac float64) time.Duration {
+	delta := float64(d) * frac
+	return d + time.Duration(rand.Float64()*2*delta-delta)
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/steipete/wacli/internal/msgstore"
+	"github.com/steipete/wacli/internal/store"
+)
+
+var (
+	exportManagersMu sync.Mutex
+	exportManagers   = map[string]*msgstore.Manager{}
+)
+
+// exportManager returns the msgstore.Manager for storeDir, building and
+// caching it on first use. Manager and its FSExporter hold open file handles
+// per chat/day, so a fresh Manager (and the Close that follows it) per
+// message reopened and reclosed the day-log on every single write; one
+// Manager per storeDir is kept for the life of the process instead.
+func exportManager(storeDir string) *msgstore.Manager {
+	exportManagersMu.Lock()
+	defer exportManagersMu.Unlock()
+	if mgr, ok := exportManagers[storeDir]; ok {
+		return mgr
+	}
+	mgr := msgstore.NewManager(defaultExporters(storeDir)...)
+	exportManagers[storeDir] = mgr
+	return mgr
+}
+
+// defaultExporters is the exporter set RecordMessage fans writes out to.
+// msgstore.DBExporter is a no-op (the write already landed in SQLite via
+// UpsertMessage) but is listed alongside FSExporter so that turning
+// individual exporters on or off stays a one-line change here rather than
+// FSExporter being hardcoded as the only option RecordMessage knows about.
+func defaultExporters(storeDir string) []msgstore.Exporter {
+	return []msgstore.Exporter{
+		msgstore.NewFSExporter(storeDir),
+		msgstore.NewDBExporter(),
+	}
+}
+
+// RecordMessage is the single choke point for persisting a message, inbound
+// or outbound: it upserts into the SQLite store and mirrors the same write
+// through the configured exporters (see defaultExporters), so every write
+// path (send, incoming sync) stays consistent rather than each caller
+// remembering to invoke the exporter itself. The mirror is best-effort,
+// matching UpsertMessage's own best-effort call sites: a failing exporter is
+// logged, not returned.
+func (a *App) RecordMessage(p store.UpsertMessageParams) error {
+	if err := a.db.UpsertMessage(p); err != nil {
+		return err
+	}
+
+	mgr := exportManager(a.StoreDir())
+	if err := mgr.Export(store.Message{
+		ChatJID:    p.ChatJID,
+		ChatName:   p.ChatName,
+		MsgID:      p.MsgID,
+		SenderJID:  p.SenderJID,
+		SenderName: p.SenderName,
+		Timestamp:  p.Timestamp,
+		FromMe:     p.FromMe,
+		Text:       p.Text,
+	}); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: log mirror failed: %v\n", err)
+	}
+	return nil
+}
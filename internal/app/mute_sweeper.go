@@ -0,0 +1,54 @@
+package app
+
+import (
+	"context"
+	"time"
+)
+
+// MuteSweeper periodically clears chats whose mute has expired. Nothing
+// else polls muted_until once MuteChat sets it, so without this a
+// time-boxed mute would stay set in the local store (and keep filtering
+// `chats list --muted`) long after WhatsApp itself has unmuted the chat.
+type MuteSweeper struct {
+	app      *App
+	interval time.Duration
+}
+
+// NewMuteSweeper returns a sweeper that checks for expired mutes every
+// interval (defaulting to 30s if interval is non-positive).
+func NewMuteSweeper(a *App, interval time.Duration) *MuteSweeper {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	return &MuteSweeper{app: a, interval: interval}
+}
+
+// Run polls for expired mutes until ctx is done, calling onUnmute(jid) for
+// each chat it clears.
+func (s *MuteSweeper) Run(ctx context.Context, onUnmute func(jid string)) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.sweep(onUnmute)
+		}
+	}
+}
+
+func (s *MuteSweeper) sweep(onUnmute func(jid string)) {
+	jids, err := s.app.db.ListExpiredMutes(time.Now())
+	if err != nil {
+		return
+	}
+	for _, jid := range jids {
+		if err := s.app.db.SetChatMutedUntil(jid, 0); err != nil {
+			continue
+		}
+		if onUnmute != nil {
+			onUnmute(jid)
+		}
+	}
+}
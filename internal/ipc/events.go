@@ -0,0 +1,110 @@
+package ipc
+
+import (
+	"sync"
+	"time"
+)
+
+// Event is a single item on the IPC event stream delivered to "subscribe"
+// connections as a newline-delimited JSON envelope (distinct from a command
+// Response). It mirrors how mautrix-whatsapp and slidge push whatsmeow
+// events to bridge consumers.
+type Event struct {
+	Type      string    `json:"type"` // "message", "message_edit", "message_revoke", "reaction", "receipt", "chat_state", "presence", "connect", "disconnect", "heartbeat"
+	Timestamp time.Time `json:"timestamp"`
+	ChatJID   string    `json:"chat_jid,omitempty"`
+	Data      any       `json:"data,omitempty"`
+}
+
+// SubscribeFilter narrows a subscription to specific chats and/or event
+// types; either left empty matches everything.
+type SubscribeFilter struct {
+	Chats      []string `json:"chats,omitempty"`
+	EventTypes []string `json:"event_types,omitempty"`
+}
+
+func (f SubscribeFilter) matches(evt Event) bool {
+	if len(f.Chats) > 0 {
+		found := false
+		for _, c := range f.Chats {
+			if c == evt.ChatJID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(f.EventTypes) > 0 {
+		found := false
+		for _, t := range f.EventTypes {
+			if t == evt.Type {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// EventBus fans Publish calls out to every subscribed "subscribe"
+// connection, applying each subscriber's filter.
+type EventBus struct {
+	mu     sync.Mutex
+	nextID int
+	subs   map[int]*eventSub
+}
+
+type eventSub struct {
+	filter SubscribeFilter
+	ch     chan Event
+}
+
+// NewEventBus returns an empty bus.
+func NewEventBus() *EventBus {
+	return &EventBus{subs: make(map[int]*eventSub)}
+}
+
+// Subscribe registers a new listener matching filter and returns its id
+// (for Unsubscribe) and a channel receiving every matching Event published
+// from here on. The channel is buffered; a slow subscriber drops events
+// rather than blocking Publish for everyone else.
+func (b *EventBus) Subscribe(filter SubscribeFilter) (int, <-chan Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := b.nextID
+	b.nextID++
+	sub := &eventSub{filter: filter, ch: make(chan Event, 32)}
+	b.subs[id] = sub
+	return id, sub.ch
+}
+
+// Unsubscribe removes a listener and closes its channel.
+func (b *EventBus) Unsubscribe(id int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if sub, ok := b.subs[id]; ok {
+		close(sub.ch)
+		delete(b.subs, id)
+	}
+}
+
+// Publish delivers evt to every subscriber whose filter matches it,
+// dropping it for any subscriber whose buffer is full.
+func (b *EventBus) Publish(evt Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, sub := range b.subs {
+		if !sub.filter.matches(evt) {
+			continue
+		}
+		select {
+		case sub.ch <- evt:
+		default:
+		}
+	}
+}
@@ -8,18 +8,27 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"strconv"
 	"sync"
 	"time"
+
+	"github.com/steipete/wacli/internal/store"
 )
 
 const (
-	socketName    = "wacli.sock"
-	readTimeout   = 30 * time.Second
-	writeTimeout  = 30 * time.Second
+	socketName       = "wacli.sock"
+	readTimeout      = 30 * time.Second
+	writeTimeout     = 30 * time.Second
+	heartbeatEvery   = 30 * time.Second
+	tokenReloadEvery = 5 * time.Second
 )
 
-// Request represents a command sent to the sync daemon.
+// Request represents a command sent to the sync daemon. ID, if set, is
+// echoed back on the matching Response (JSON-RPC 2.0-style) so a caller
+// pipelining several requests over one persistent connection can correlate
+// them; it is optional for the one-request-per-connection style too.
 type Request struct {
+	ID          string `json:"id,omitempty"`
 	Command     string `json:"command"` // "send_text", "send_file", "delete_message", "chat_state", "ping"
 	To          string `json:"to,omitempty"`
 	Message     string `json:"message,omitempty"`
@@ -28,12 +37,42 @@ type Request struct {
 	Chat        string `json:"chat,omitempty"`
 	MsgID       string `json:"msg_id,omitempty"`
 	ForEveryone bool   `json:"for_everyone,omitempty"`
-	Action      string `json:"action,omitempty"`   // chat_state: archive, unarchive, pin, unpin, mute, unmute, mark-read, mark-unread
-	Duration    string `json:"duration,omitempty"` // chat_state mute: duration string (e.g. "8h")
+	Action      string `json:"action,omitempty"`     // chat_state: archive, unarchive, pin, unpin, mute, unmute, mark-read, mark-unread
+	Duration    string `json:"duration,omitempty"`   // chat_state mute: duration string (e.g. "8h")
+	Query       string `json:"query,omitempty"`      // search_messages: FTS query terms
+	Sender      string `json:"sender,omitempty"`     // search_messages: sender JID filter
+	Since       string `json:"since,omitempty"`      // search_messages: RFC3339 lower bound
+	Until       string `json:"until,omitempty"`      // search_messages: RFC3339 upper bound
+	FromMe      *bool  `json:"from_me,omitempty"`    // search_messages: restrict by direction
+	Limit       int    `json:"limit,omitempty"`      // search_messages: page size
+	PageToken   string `json:"page_token,omitempty"` // search_messages: resume cursor
+	Verb        string `json:"verb,omitempty"`       // history: BEFORE, AFTER, LATEST, AROUND, BETWEEN
+	Anchor      string `json:"anchor,omitempty"`     // history: msgid or timestamp
+	AnchorEnd   string `json:"anchor_end,omitempty"` // history BETWEEN: end of range
+
+	// subscribe: upgrades the connection into a streaming event feed (see
+	// Event) instead of one request/one response. Filter narrows which
+	// events are delivered; either field left empty matches everything.
+	Chats      []string `json:"chats,omitempty"`
+	EventTypes []string `json:"event_types,omitempty"`
+
+	// hello: authenticates the connection before any other command is
+	// accepted. Only required once at least one token is registered in
+	// storeDir/ipc_tokens.json.
+	Token string `json:"token,omitempty"`
+
+	Mimetype     string  `json:"mimetype,omitempty"`      // send_file: MIME type (auto-detected from File if empty)
+	Emoji        string  `json:"emoji,omitempty"`         // send_reaction: reaction emoji (empty clears the reaction)
+	Latitude     float64 `json:"latitude,omitempty"`      // send_location
+	Longitude    float64 `json:"longitude,omitempty"`     // send_location
+	LocationName string  `json:"location_name,omitempty"` // send_location: optional label
+	Available    *bool   `json:"available,omitempty"`     // set_presence: true online, false offline
 }
 
-// Response represents the result from the sync daemon.
+// Response represents the result from the sync daemon. ID mirrors the
+// triggering Request's ID.
 type Response struct {
+	ID      string `json:"id,omitempty"`
 	Success bool   `json:"success"`
 	Error   string `json:"error,omitempty"`
 	Data    any    `json:"data,omitempty"`
@@ -45,31 +84,97 @@ type SendTextResult struct {
 	MsgID string `json:"msg_id"`
 }
 
+// SendFileResult is returned for send_file commands.
+type SendFileResult struct {
+	To    string `json:"to"`
+	MsgID string `json:"msg_id"`
+}
+
+// SearchMessagesResult is returned for search_messages commands.
+type SearchMessagesResult struct {
+	Messages      []MessageHit `json:"messages"`
+	NextPageToken string       `json:"next_page_token,omitempty"`
+	PrevPageToken string       `json:"prev_page_token,omitempty"`
+}
+
+// MessageHit is a single search_messages result. Snippet holds the message
+// text as stored; matched terms are not highlighted.
+type MessageHit struct {
+	ChatJID    string `json:"chat_jid"`
+	MsgID      string `json:"msg_id"`
+	SenderName string `json:"sender_name"`
+	Timestamp  int64  `json:"ts"`
+	FromMe     bool   `json:"from_me"`
+	Snippet    string `json:"snippet"`
+}
+
+// HistoryResult is returned for history commands (BEFORE/AFTER/LATEST/AROUND/BETWEEN).
+type HistoryResult struct {
+	Messages []store.Message `json:"messages"`
+}
+
 // Handler processes incoming IPC requests.
 type Handler interface {
 	SendText(to, message string) (msgID string, err error)
 	DeleteMessage(chat, msgID string, forEveryone bool) error
 	ChatState(jid, action, duration string) error
+	SearchMessages(query, chat, sender, since, until string, fromMe *bool, limit int, pageToken string) (SearchMessagesResult, error)
+	// History implements the IRCv3 CHATHISTORY-style verbs: BEFORE, AFTER,
+	// LATEST, AROUND each take a single anchor; BETWEEN takes anchor as the
+	// range start and anchorEnd as the range end.
+	History(jid, verb, anchor, anchorEnd string, limit int) (HistoryResult, error)
+	// Status reports the daemon's current connection health.
+	Status() (Status, error)
+	// SendFile uploads the file at path and sends it to to, with caption
+	// and mimetype auto-detecting the message kind (image/video/audio/document).
+	SendFile(to, path, caption, mimetype string) (msgID string, err error)
+	// SendReaction reacts to chat/msgID with emoji (empty clears it).
+	SendReaction(chat, msgID, emoji string, fromMe bool) error
+	// SendLocation sends a one-off location pin.
+	SendLocation(to string, lat, lng float64, name string) error
+	// SetPresence broadcasts our global availability.
+	SetPresence(available bool) error
 }
 
 // Server listens on a Unix socket for IPC requests.
 type Server struct {
 	storeDir string
 	handler  Handler
+	events   *EventBus
+	tokens   *TokenStore
 	listener net.Listener
 	wg       sync.WaitGroup
 	done     chan struct{}
 }
 
-// NewServer creates an IPC server.
+// NewServer creates an IPC server. It loads storeDir/ipc_tokens.json, if
+// present, to decide whether to enforce the "hello" auth handshake.
 func NewServer(storeDir string, handler Handler) *Server {
+	tokens, err := LoadTokenStore(storeDir)
+	if err != nil {
+		tokens = &TokenStore{path: filepath.Join(storeDir, tokensFileName)}
+	}
 	return &Server{
 		storeDir: storeDir,
 		handler:  handler,
+		events:   NewEventBus(),
+		tokens:   tokens,
 		done:     make(chan struct{}),
 	}
 }
 
+// Publish delivers evt to every connection currently streaming via
+// "subscribe", matching each subscriber's filter.
+func (s *Server) Publish(evt Event) {
+	s.events.Publish(evt)
+}
+
+// Events returns the server's event bus so callers (e.g. the command
+// handler) can publish events without holding their own bus reference.
+func (s *Server) Events() *EventBus {
+	return s.events
+}
+
 // SocketPath returns the path to the Unix socket.
 func SocketPath(storeDir string) string {
 	return filepath.Join(storeDir, socketName)
@@ -78,22 +183,42 @@ func SocketPath(storeDir string) string {
 // Start begins listening for connections.
 func (s *Server) Start() error {
 	sockPath := SocketPath(s.storeDir)
-	
+
 	// Remove stale socket if exists
 	_ = os.Remove(sockPath)
-	
+
 	listener, err := net.Listen("unix", sockPath)
 	if err != nil {
 		return fmt.Errorf("listen on socket: %w", err)
 	}
 	s.listener = listener
-	
+
 	s.wg.Add(1)
 	go s.acceptLoop()
-	
+
+	s.wg.Add(1)
+	go s.reloadTokensLoop()
+
 	return nil
 }
 
+// reloadTokensLoop periodically re-reads ipc_tokens.json so `ipc token
+// add`/`ipc token revoke`, run from a separate CLI invocation, take effect
+// on this already-running daemon without a restart.
+func (s *Server) reloadTokensLoop() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(tokenReloadEvery)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			_ = s.tokens.Reload()
+		}
+	}
+}
+
 // Stop shuts down the server.
 func (s *Server) Stop() {
 	close(s.done)
@@ -112,7 +237,7 @@ func (s *Server) acceptLoop() {
 			return
 		default:
 		}
-		
+
 		conn, err := s.listener.Accept()
 		if err != nil {
 			select {
@@ -122,47 +247,153 @@ func (s *Server) acceptLoop() {
 				continue
 			}
 		}
-		
+
 		s.wg.Add(1)
 		go s.handleConn(conn)
 	}
 }
 
+// handleConn serves a connection as a persistent, pipelined request/response
+// stream: it loops reading one JSON request per line until EOF or a read
+// error, dispatching each through processRequest and writing back a tagged
+// Response (Response.ID mirrors Request.ID, JSON-RPC 2.0-style) so a caller
+// sending several requests without waiting for replies can still correlate
+// them. Auth state from a "hello" frame, once established, applies to every
+// subsequent request on the connection. A "subscribe" request hands the
+// connection off to handleSubscribe, which owns it until disconnect.
 func (s *Server) handleConn(conn net.Conn) {
 	defer s.wg.Done()
 	defer conn.Close()
-	
+
 	// Recover from panics in the handler
 	defer func() {
 		if r := recover(); r != nil {
 			s.writeResponse(conn, Response{Success: false, Error: fmt.Sprintf("internal error: %v", r)})
 		}
 	}()
-	
-	_ = conn.SetReadDeadline(time.Now().Add(readTimeout))
-	
+
 	reader := bufio.NewReader(conn)
-	line, err := reader.ReadBytes('\n')
-	if err != nil {
-		s.writeResponse(conn, Response{Success: false, Error: fmt.Sprintf("read error: %v", err)})
-		return
+	var authed *TokenEntry
+
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(readTimeout))
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			return
+		}
+
+		var req Request
+		if err := json.Unmarshal(line, &req); err != nil {
+			s.writeResponse(conn, Response{Success: false, Error: fmt.Sprintf("invalid request: %v", err)})
+			continue
+		}
+
+		if req.Command == "hello" {
+			entry, ok := s.tokens.Authenticate(req.Token)
+			if !ok {
+				s.writeResponse(conn, Response{ID: req.ID, Success: false, Error: "invalid token"})
+				continue
+			}
+			authed = &entry
+			s.writeResponse(conn, Response{ID: req.ID, Success: true, Data: "hello"})
+			continue
+		}
+
+		if authed == nil && s.tokens.Enabled() && req.Command != "ping" {
+			s.writeResponse(conn, Response{ID: req.ID, Success: false, Error: "authentication required: send a hello frame first"})
+			continue
+		}
+
+		if authed != nil {
+			if !authed.allowsCommand(req.Command) {
+				s.writeResponse(conn, Response{ID: req.ID, Success: false, Error: fmt.Sprintf("token %q is not allowed to run %q", authed.Name, req.Command)})
+				continue
+			}
+			if !authed.allowsChat(req.Chat) || !authed.allowsChat(req.To) || !authed.allowsChats(req.Chats) {
+				s.writeResponse(conn, Response{ID: req.ID, Success: false, Error: fmt.Sprintf("token %q is not allowed to act on this chat", authed.Name)})
+				continue
+			}
+		}
+
+		if req.Command == "subscribe" {
+			s.handleSubscribe(conn, req)
+			return
+		}
+
+		resp := s.processRequest(req)
+		resp.ID = req.ID
+		s.writeResponse(conn, resp)
 	}
-	
-	var req Request
-	if err := json.Unmarshal(line, &req); err != nil {
-		s.writeResponse(conn, Response{Success: false, Error: fmt.Sprintf("invalid request: %v", err)})
-		return
+}
+
+// handleSubscribe upgrades conn into a long-lived event stream: one ack
+// Response, then one JSON Event per line until the client disconnects.
+// Unlike other commands, this holds the connection open: reads carry no
+// deadline, and only each event write is bounded by writeTimeout.
+func (s *Server) handleSubscribe(conn net.Conn, req Request) {
+	filter := SubscribeFilter{Chats: req.Chats, EventTypes: req.EventTypes}
+	s.writeResponse(conn, Response{ID: req.ID, Success: true, Data: "subscribed"})
+
+	id, events := s.events.Subscribe(filter)
+	defer s.events.Unsubscribe(id)
+
+	// Clear the read deadline handleConn set for the subscribe request, so
+	// the disconnect watcher below does not time out after readTimeout.
+	_ = conn.SetReadDeadline(time.Time{})
+
+	// Detect client disconnect by watching for EOF on reads; subscribe
+	// connections never send further requests.
+	disconnected := make(chan struct{})
+	go func() {
+		defer close(disconnected)
+		buf := make([]byte, 1)
+		for {
+			if _, err := conn.Read(buf); err != nil {
+				return
+			}
+		}
+	}()
+
+	heartbeat := time.NewTicker(heartbeatEvery)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-disconnected:
+			return
+		case evt, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := s.writeEvent(conn, evt); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err := s.writeEvent(conn, Event{Type: "heartbeat", Timestamp: time.Now()}); err != nil {
+				return
+			}
+		}
 	}
-	
-	resp := s.processRequest(req)
-	s.writeResponse(conn, resp)
+}
+
+func (s *Server) writeEvent(conn net.Conn, evt Event) error {
+	_ = conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	_, err = conn.Write(data)
+	return err
 }
 
 func (s *Server) processRequest(req Request) Response {
 	switch req.Command {
 	case "ping":
 		return Response{Success: true, Data: "pong"}
-	
+
 	case "send_text":
 		if req.To == "" || req.Message == "" {
 			return Response{Success: false, Error: "to and message are required"}
@@ -172,7 +403,7 @@ func (s *Server) processRequest(req Request) Response {
 			return Response{Success: false, Error: err.Error()}
 		}
 		return Response{Success: true, Data: SendTextResult{To: req.To, MsgID: msgID}}
-	
+
 	case "delete_message":
 		if req.Chat == "" || req.MsgID == "" {
 			return Response{Success: false, Error: "chat and msg_id are required"}
@@ -202,6 +433,68 @@ func (s *Server) processRequest(req Request) Response {
 			"ok":     true,
 		}}
 
+	case "search_messages":
+		res, err := s.handler.SearchMessages(req.Query, req.Chat, req.Sender, req.Since, req.Until, req.FromMe, req.Limit, req.PageToken)
+		if err != nil {
+			return Response{Success: false, Error: err.Error()}
+		}
+		return Response{Success: true, Data: res}
+
+	case "history":
+		if req.Chat == "" || req.Verb == "" {
+			return Response{Success: false, Error: "chat and verb are required"}
+		}
+		res, err := s.handler.History(req.Chat, req.Verb, req.Anchor, req.AnchorEnd, req.Limit)
+		if err != nil {
+			return Response{Success: false, Error: err.Error()}
+		}
+		return Response{Success: true, Data: res}
+
+	case "status":
+		st, err := s.handler.Status()
+		if err != nil {
+			return Response{Success: false, Error: err.Error()}
+		}
+		return Response{Success: true, Data: st}
+
+	case "send_file":
+		if req.To == "" || req.File == "" {
+			return Response{Success: false, Error: "to and file are required"}
+		}
+		msgID, err := s.handler.SendFile(req.To, req.File, req.Caption, req.Mimetype)
+		if err != nil {
+			return Response{Success: false, Error: err.Error()}
+		}
+		return Response{Success: true, Data: SendFileResult{To: req.To, MsgID: msgID}}
+
+	case "send_reaction":
+		if req.Chat == "" || req.MsgID == "" {
+			return Response{Success: false, Error: "chat and msg_id are required"}
+		}
+		fromMe := req.FromMe != nil && *req.FromMe
+		if err := s.handler.SendReaction(req.Chat, req.MsgID, req.Emoji, fromMe); err != nil {
+			return Response{Success: false, Error: err.Error()}
+		}
+		return Response{Success: true, Data: map[string]any{"reacted": true}}
+
+	case "send_location":
+		if req.To == "" {
+			return Response{Success: false, Error: "to is required"}
+		}
+		if err := s.handler.SendLocation(req.To, req.Latitude, req.Longitude, req.LocationName); err != nil {
+			return Response{Success: false, Error: err.Error()}
+		}
+		return Response{Success: true, Data: map[string]any{"sent": true}}
+
+	case "set_presence":
+		if req.Available == nil {
+			return Response{Success: false, Error: "available is required"}
+		}
+		if err := s.handler.SetPresence(*req.Available); err != nil {
+			return Response{Success: false, Error: err.Error()}
+		}
+		return Response{Success: true, Data: map[string]any{"available": *req.Available}}
+
 	default:
 		return Response{Success: false, Error: fmt.Sprintf("unknown command: %s", req.Command)}
 	}
@@ -217,6 +510,7 @@ func (s *Server) writeResponse(conn net.Conn, resp Response) {
 // Client connects to a running sync daemon.
 type Client struct {
 	storeDir string
+	token    string
 }
 
 // NewClient creates an IPC client.
@@ -224,6 +518,13 @@ func NewClient(storeDir string) *Client {
 	return &Client{storeDir: storeDir}
 }
 
+// SetToken configures the token sent in a "hello" frame before every
+// request, for daemons that enforce auth (see TokenStore). A zero-value
+// Client (no token set) talks to un-provisioned daemons exactly as before.
+func (c *Client) SetToken(token string) {
+	c.token = token
+}
+
 // IsAvailable checks if the sync daemon socket exists.
 func (c *Client) IsAvailable() bool {
 	sockPath := SocketPath(c.storeDir)
@@ -238,23 +539,23 @@ func (c *Client) SendText(to, message string) (*SendTextResult, error) {
 		To:      to,
 		Message: message,
 	}
-	
+
 	resp, err := c.send(req)
 	if err != nil {
 		return nil, err
 	}
-	
+
 	if !resp.Success {
 		return nil, fmt.Errorf("%s", resp.Error)
 	}
-	
+
 	// Parse the result
 	data, _ := json.Marshal(resp.Data)
 	var result SendTextResult
 	if err := json.Unmarshal(data, &result); err != nil {
 		return nil, fmt.Errorf("parse response: %w", err)
 	}
-	
+
 	return &result, nil
 }
 
@@ -279,16 +580,16 @@ func (c *Client) DeleteMessage(chat, msgID string, forEveryone bool) error {
 		MsgID:       msgID,
 		ForEveryone: forEveryone,
 	}
-	
+
 	resp, err := c.send(req)
 	if err != nil {
 		return err
 	}
-	
+
 	if !resp.Success {
 		return fmt.Errorf("%s", resp.Error)
 	}
-	
+
 	return nil
 }
 
@@ -313,32 +614,369 @@ func (c *Client) ChatState(jid, action, duration string) error {
 	return nil
 }
 
-func (c *Client) send(req Request) (*Response, error) {
+// SearchMessages runs a full-text search via the sync daemon.
+func (c *Client) SearchMessages(query, chat, sender, since, until string, fromMe *bool, limit int, pageToken string) (*SearchMessagesResult, error) {
+	req := Request{
+		Command:   "search_messages",
+		Query:     query,
+		Chat:      chat,
+		Sender:    sender,
+		Since:     since,
+		Until:     until,
+		FromMe:    fromMe,
+		Limit:     limit,
+		PageToken: pageToken,
+	}
+
+	resp, err := c.send(req)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("%s", resp.Error)
+	}
+
+	data, _ := json.Marshal(resp.Data)
+	var result SearchMessagesResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	return &result, nil
+}
+
+// History runs a CHATHISTORY-style query (verb is one of BEFORE, AFTER,
+// LATEST, AROUND, BETWEEN) via the sync daemon.
+func (c *Client) History(jid, verb, anchor, anchorEnd string, limit int) (*HistoryResult, error) {
+	req := Request{
+		Command:   "history",
+		Chat:      jid,
+		Verb:      verb,
+		Anchor:    anchor,
+		AnchorEnd: anchorEnd,
+		Limit:     limit,
+	}
+
+	resp, err := c.send(req)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("%s", resp.Error)
+	}
+
+	data, _ := json.Marshal(resp.Data)
+	var result HistoryResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	return &result, nil
+}
+
+// SendFile uploads the file at path and sends it via the sync daemon.
+func (c *Client) SendFile(to, path, caption, mimetype string) (*SendFileResult, error) {
+	req := Request{
+		Command:  "send_file",
+		To:       to,
+		File:     path,
+		Caption:  caption,
+		Mimetype: mimetype,
+	}
+
+	resp, err := c.send(req)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("%s", resp.Error)
+	}
+
+	data, _ := json.Marshal(resp.Data)
+	var result SendFileResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	return &result, nil
+}
+
+// SendReaction reacts to chat/msgID with emoji (empty clears it) via the
+// sync daemon.
+func (c *Client) SendReaction(chat, msgID, emoji string, fromMe bool) error {
+	req := Request{
+		Command: "send_reaction",
+		Chat:    chat,
+		MsgID:   msgID,
+		Emoji:   emoji,
+		FromMe:  &fromMe,
+	}
+	resp, err := c.send(req)
+	if err != nil {
+		return err
+	}
+	if !resp.Success {
+		return fmt.Errorf("%s", resp.Error)
+	}
+	return nil
+}
+
+// SendLocation sends a one-off location pin via the sync daemon.
+func (c *Client) SendLocation(to string, lat, lng float64, name string) error {
+	req := Request{
+		Command:      "send_location",
+		To:           to,
+		Latitude:     lat,
+		Longitude:    lng,
+		LocationName: name,
+	}
+	resp, err := c.send(req)
+	if err != nil {
+		return err
+	}
+	if !resp.Success {
+		return fmt.Errorf("%s", resp.Error)
+	}
+	return nil
+}
+
+// SetPresence broadcasts our global availability via the sync daemon.
+func (c *Client) SetPresence(available bool) error {
+	req := Request{Command: "set_presence", Available: &available}
+	resp, err := c.send(req)
+	if err != nil {
+		return err
+	}
+	if !resp.Success {
+		return fmt.Errorf("%s", resp.Error)
+	}
+	return nil
+}
+
+// Status fetches the daemon's current connection health.
+func (c *Client) Status() (*Status, error) {
+	resp, err := c.send(Request{Command: "status"})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("%s", resp.Error)
+	}
+
+	data, _ := json.Marshal(resp.Data)
+	var st Status
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	return &st, nil
+}
+
+// Subscription is a live "subscribe" connection to the sync daemon.
+type Subscription struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// Subscribe opens a long-lived event stream matching filter. The caller
+// must call Next in a loop (or from a goroutine) and Close when done.
+func (c *Client) Subscribe(filter SubscribeFilter) (*Subscription, error) {
 	sockPath := SocketPath(c.storeDir)
 	conn, err := net.DialTimeout("unix", sockPath, 5*time.Second)
 	if err != nil {
 		return nil, fmt.Errorf("connect to daemon: %w", err)
 	}
-	defer conn.Close()
-	
-	_ = conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+
+	reader := bufio.NewReader(conn)
+	if c.token != "" {
+		if _, err := sendHello(conn, reader, c.token); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	req := Request{Command: "subscribe", Chats: filter.Chats, EventTypes: filter.EventTypes}
 	data, _ := json.Marshal(req)
 	data = append(data, '\n')
+	_ = conn.SetWriteDeadline(time.Now().Add(writeTimeout))
 	if _, err := conn.Write(data); err != nil {
-		return nil, fmt.Errorf("write request: %w", err)
+		conn.Close()
+		return nil, fmt.Errorf("write subscribe request: %w", err)
 	}
-	
+
 	_ = conn.SetReadDeadline(time.Now().Add(readTimeout))
-	reader := bufio.NewReader(conn)
 	line, err := reader.ReadBytes('\n')
 	if err != nil {
-		return nil, fmt.Errorf("read response: %w", err)
+		conn.Close()
+		return nil, fmt.Errorf("read subscribe ack: %w", err)
+	}
+	var ack Response
+	if err := json.Unmarshal(line, &ack); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("parse subscribe ack: %w", err)
+	}
+	if !ack.Success {
+		conn.Close()
+		return nil, fmt.Errorf("%s", ack.Error)
+	}
+
+	// Subscription connections are long-lived by design; no further
+	// read deadline is set so Next can block indefinitely between events.
+	_ = conn.SetReadDeadline(time.Time{})
+
+	return &Subscription{conn: conn, reader: reader}, nil
+}
+
+// Next blocks until the next Event arrives, or returns an error (including
+// io.EOF) if the connection is closed.
+func (s *Subscription) Next() (Event, error) {
+	line, err := s.reader.ReadBytes('\n')
+	if err != nil {
+		return Event{}, err
+	}
+	var evt Event
+	if err := json.Unmarshal(line, &evt); err != nil {
+		return Event{}, fmt.Errorf("parse event: %w", err)
+	}
+	return evt, nil
+}
+
+// Close ends the subscription.
+func (s *Subscription) Close() error {
+	return s.conn.Close()
+}
+
+func (c *Client) send(req Request) (*Response, error) {
+	conn, err := c.Dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := conn.Send(req); err != nil {
+		return nil, err
+	}
+	resp, err := conn.Recv()
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Conn is a low-level, persistent connection to the sync daemon that a
+// caller can keep open across many requests, pipelining several Sends
+// before reading their Recvs back (see Batch). Unlike the one-shot send
+// helper, it performs the "hello" handshake once at Dial time rather than
+// per request.
+type Conn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// Dial opens a new connection to the sync daemon, authenticating with the
+// client's token (if set) before returning.
+func (c *Client) Dial() (*Conn, error) {
+	sockPath := SocketPath(c.storeDir)
+	netConn, err := net.DialTimeout("unix", sockPath, 5*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("connect to daemon: %w", err)
+	}
+
+	reader := bufio.NewReader(netConn)
+	if c.token != "" {
+		if _, err := sendHello(netConn, reader, c.token); err != nil {
+			netConn.Close()
+			return nil, err
+		}
+	}
+
+	return &Conn{conn: netConn, reader: reader}, nil
+}
+
+// Send writes req to the connection.
+func (c *Conn) Send(req Request) error {
+	_ = c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	data, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+	data = append(data, '\n')
+	if _, err := c.conn.Write(data); err != nil {
+		return fmt.Errorf("write request: %w", err)
+	}
+	return nil
+}
+
+// Recv reads the next response from the connection.
+func (c *Conn) Recv() (Response, error) {
+	_ = c.conn.SetReadDeadline(time.Now().Add(readTimeout))
+	line, err := c.reader.ReadBytes('\n')
+	if err != nil {
+		return Response{}, fmt.Errorf("read response: %w", err)
 	}
-	
 	var resp Response
 	if err := json.Unmarshal(line, &resp); err != nil {
-		return nil, fmt.Errorf("parse response: %w", err)
+		return Response{}, fmt.Errorf("parse response: %w", err)
 	}
-	
-	return &resp, nil
+	return resp, nil
+}
+
+// Close ends the connection.
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+// Batch pipelines reqs over a single connection: every request is written
+// before any response is read, so the round-trip cost of dialing and
+// waiting is paid once for the whole batch rather than once per request.
+// Responses are returned in the same order as reqs, regardless of the IDs
+// the daemon echoes back. Any request missing an ID is assigned one
+// (its index as a string) so the two can still be correlated by a caller
+// inspecting Response.ID directly.
+func (c *Client) Batch(reqs []Request) ([]Response, error) {
+	conn, err := c.Dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	for i := range reqs {
+		if reqs[i].ID == "" {
+			reqs[i].ID = strconv.Itoa(i)
+		}
+		if err := conn.Send(reqs[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	resps := make([]Response, len(reqs))
+	for i := range reqs {
+		resp, err := conn.Recv()
+		if err != nil {
+			return nil, err
+		}
+		resps[i] = resp
+	}
+	return resps, nil
+}
+
+// sendHello writes a "hello" frame with token and reads its ack, returning
+// an error if the daemon rejects it.
+func sendHello(conn net.Conn, reader *bufio.Reader, token string) (*Response, error) {
+	_ = conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	data, _ := json.Marshal(Request{Command: "hello", Token: token})
+	data = append(data, '\n')
+	if _, err := conn.Write(data); err != nil {
+		return nil, fmt.Errorf("write hello: %w", err)
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(readTimeout))
+	line, err := reader.ReadBytes('\n')
+	if err != nil {
+		return nil, fmt.Errorf("read hello ack: %w", err)
+	}
+	var ack Response
+	if err := json.Unmarshal(line, &ack); err != nil {
+		return nil, fmt.Errorf("parse hello ack: %w", err)
+	}
+	if !ack.Success {
+		return nil, fmt.Errorf("%s", ack.Error)
+	}
+	return &ack, nil
 }
@@ -0,0 +1,90 @@
+package ipc
+
+import (
+	"sync"
+	"time"
+)
+
+// HealthState mirrors the small state machine bridge daemons (e.g. Matrix
+// app-service bridges) expose so a supervisor can tell "no news" apart from
+// "actively broken" without parsing logs.
+type HealthState string
+
+const (
+	StateStarting            HealthState = "STARTING"
+	StateConnecting          HealthState = "CONNECTING"
+	StateConnected           HealthState = "CONNECTED"
+	StateTransientDisconnect HealthState = "TRANSIENT_DISCONNECT"
+	StateLoggedOut           HealthState = "LOGGED_OUT"
+	StateBadCredentials      HealthState = "BAD_CREDENTIALS"
+	StateUnknownError        HealthState = "UNKNOWN_ERROR"
+)
+
+// Status is a point-in-time health report. TTL tells a consumer how long
+// Timestamp should be trusted before treating the daemon as unresponsive,
+// the way a bridge's STARTING state expires if CONNECTED never follows.
+type Status struct {
+	State     HealthState       `json:"state"`
+	Timestamp time.Time         `json:"timestamp"`
+	TTL       time.Duration     `json:"ttl,omitempty"`
+	Reason    string            `json:"reason,omitempty"`
+	Info      map[string]string `json:"info,omitempty"`
+}
+
+// Stale reports whether st was last updated more than its TTL ago. A
+// zero TTL never goes stale.
+func (st Status) Stale(now time.Time) bool {
+	if st.TTL <= 0 {
+		return false
+	}
+	return now.After(st.Timestamp.Add(st.TTL))
+}
+
+// StatusTracker holds the daemon's current health state behind a mutex so
+// the IPC handler goroutine and the connection-management goroutine can
+// both read and update it safely.
+type StatusTracker struct {
+	mu  sync.RWMutex
+	cur Status
+
+	onChange []func(Status)
+}
+
+// NewStatusTracker returns a tracker initialized to StateStarting.
+func NewStatusTracker() *StatusTracker {
+	return &StatusTracker{cur: Status{State: StateStarting, Timestamp: time.Now()}}
+}
+
+// OnChange registers a callback invoked (synchronously, after the lock is
+// released) every time Set installs a new status. Multiple callbacks may be
+// registered; each is called in registration order.
+func (t *StatusTracker) OnChange(fn func(Status)) {
+	t.mu.Lock()
+	t.onChange = append(t.onChange, fn)
+	t.mu.Unlock()
+}
+
+// Set installs a new status with the given reason/info and the default 2
+// minute TTL, then notifies the OnChange callbacks if any are registered.
+func (t *StatusTracker) Set(state HealthState, reason string, info map[string]string) {
+	t.SetTTL(state, reason, info, 2*time.Minute)
+}
+
+// SetTTL is Set with an explicit TTL (0 disables staleness checking).
+func (t *StatusTracker) SetTTL(state HealthState, reason string, info map[string]string, ttl time.Duration) {
+	st := Status{State: state, Timestamp: time.Now(), TTL: ttl, Reason: reason, Info: info}
+	t.mu.Lock()
+	t.cur = st
+	callbacks := append([]func(Status){}, t.onChange...)
+	t.mu.Unlock()
+	for _, fn := range callbacks {
+		fn(st)
+	}
+}
+
+// Get returns the current status.
+func (t *StatusTracker) Get() Status {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.cur
+}
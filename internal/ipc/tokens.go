@@ -0,0 +1,216 @@
+package ipc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const tokensFileName = "ipc_tokens.json"
+
+// TokenEntry is one registered IPC credential. The plaintext token is never
+// stored, only its SHA-256 hash.
+type TokenEntry struct {
+	Name            string    `json:"name"`
+	HashedToken     string    `json:"hashed_token"`
+	AllowedCommands []string  `json:"allowed_commands,omitempty"` // empty = all commands
+	AllowedChats    []string  `json:"allowed_chats,omitempty"`    // empty = all chats
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+func (e TokenEntry) allowsCommand(cmd string) bool {
+	if len(e.AllowedCommands) == 0 {
+		return true
+	}
+	for _, c := range e.AllowedCommands {
+		if c == cmd {
+			return true
+		}
+	}
+	return false
+}
+
+func (e TokenEntry) allowsChat(chat string) bool {
+	if len(e.AllowedChats) == 0 || chat == "" {
+		return true
+	}
+	for _, c := range e.AllowedChats {
+		if c == chat {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsChats is allowsChat generalized to a "subscribe" request's Chats
+// list. An empty list there means "every chat", which a chat-scoped token
+// must not be granted — unlike the single-chat case, there is no
+// unrestricted-by-omission default here.
+func (e TokenEntry) allowsChats(chats []string) bool {
+	if len(e.AllowedChats) == 0 {
+		return true
+	}
+	if len(chats) == 0 {
+		return false
+	}
+	for _, c := range chats {
+		if !e.allowsChat(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// TokenStore is the on-disk `storeDir/ipc_tokens.json` ACL list: the
+// per-token equivalent of mautrix-whatsapp's provisioning shared secret,
+// scoped down with allowed commands / chats per entry.
+type TokenStore struct {
+	path string
+
+	mu      sync.Mutex
+	entries []TokenEntry
+}
+
+// LoadTokenStore reads storeDir/ipc_tokens.json, if present. A missing file
+// is not an error: it means no tokens are registered yet.
+func LoadTokenStore(storeDir string) (*TokenStore, error) {
+	ts := &TokenStore{path: filepath.Join(storeDir, tokensFileName)}
+	data, err := os.ReadFile(ts.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ts, nil
+		}
+		return nil, fmt.Errorf("read token store: %w", err)
+	}
+	if err := json.Unmarshal(data, &ts.entries); err != nil {
+		return nil, fmt.Errorf("parse token store: %w", err)
+	}
+	return ts, nil
+}
+
+// Enabled reports whether any token is registered. The server only enforces
+// the hello handshake once this is true, so an un-provisioned daemon keeps
+// working exactly as it did before auth was introduced.
+func (ts *TokenStore) Enabled() bool {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return len(ts.entries) > 0
+}
+
+// List returns a copy of the registered entries (without plaintext tokens,
+// which are never stored).
+func (ts *TokenStore) List() []TokenEntry {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	out := make([]TokenEntry, len(ts.entries))
+	copy(out, ts.entries)
+	return out
+}
+
+// Add generates a random token, stores its hash under name with the given
+// ACL, and returns the plaintext token. The plaintext is only ever returned
+// here — callers must print it immediately, as LoadTokenStore can never
+// recover it.
+func (ts *TokenStore) Add(name string, allowedCommands, allowedChats []string) (string, error) {
+	token, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+
+	entry := TokenEntry{
+		Name:            name,
+		HashedToken:     hashToken(token),
+		AllowedCommands: allowedCommands,
+		AllowedChats:    allowedChats,
+		CreatedAt:       time.Now(),
+	}
+
+	ts.mu.Lock()
+	ts.entries = append(ts.entries, entry)
+	err = ts.saveLocked()
+	ts.mu.Unlock()
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// Revoke removes the named entry. It returns an error if no such entry exists.
+func (ts *TokenStore) Revoke(name string) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	for i, e := range ts.entries {
+		if e.Name == name {
+			ts.entries = append(ts.entries[:i], ts.entries[i+1:]...)
+			return ts.saveLocked()
+		}
+	}
+	return fmt.Errorf("no token named %q", name)
+}
+
+// Authenticate looks up the entry whose hash matches token.
+func (ts *TokenStore) Authenticate(token string) (TokenEntry, bool) {
+	hashed := hashToken(token)
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	for _, e := range ts.entries {
+		if e.HashedToken == hashed {
+			return e, true
+		}
+	}
+	return TokenEntry{}, false
+}
+
+// Reload re-reads storeDir/ipc_tokens.json in place, so a long-running
+// server holding this TokenStore picks up `ipc token add/revoke` made by a
+// separate CLI invocation without needing a restart. A missing file clears
+// the entries, consistent with LoadTokenStore treating "missing" as
+// "no tokens registered".
+func (ts *TokenStore) Reload() error {
+	data, err := os.ReadFile(ts.path)
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if err != nil {
+		if os.IsNotExist(err) {
+			ts.entries = nil
+			return nil
+		}
+		return fmt.Errorf("read token store: %w", err)
+	}
+	var entries []TokenEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("parse token store: %w", err)
+	}
+	ts.entries = entries
+	return nil
+}
+
+func (ts *TokenStore) saveLocked() error {
+	data, err := json.MarshalIndent(ts.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal token store: %w", err)
+	}
+	if err := os.WriteFile(ts.path, data, 0o600); err != nil {
+		return fmt.Errorf("write token store: %w", err)
+	}
+	return nil
+}
+
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,123 @@
+package ipc
+
+import "testing"
+
+func TestTokenStore_AuthenticateRoundTrip(t *testing.T) {
+	ts, err := LoadTokenStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadTokenStore: %v", err)
+	}
+	if ts.Enabled() {
+		t.Fatalf("expected Enabled()=false with no tokens registered")
+	}
+
+	token, err := ts.Add("ci", nil, nil)
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if !ts.Enabled() {
+		t.Fatalf("expected Enabled()=true after Add")
+	}
+
+	entry, ok := ts.Authenticate(token)
+	if !ok || entry.Name != "ci" {
+		t.Fatalf("expected Authenticate to find entry %q, got %+v ok=%v", "ci", entry, ok)
+	}
+	if _, ok := ts.Authenticate("not-a-real-token"); ok {
+		t.Fatalf("expected Authenticate to reject an unknown token")
+	}
+}
+
+func TestTokenEntry_AllowsCommand(t *testing.T) {
+	unrestricted := TokenEntry{}
+	if !unrestricted.allowsCommand("send_text") {
+		t.Fatalf("empty AllowedCommands should allow every command")
+	}
+
+	scoped := TokenEntry{AllowedCommands: []string{"ping", "chat_state"}}
+	if !scoped.allowsCommand("ping") {
+		t.Fatalf("expected scoped token to allow a listed command")
+	}
+	if scoped.allowsCommand("send_text") {
+		t.Fatalf("expected scoped token to reject an unlisted command")
+	}
+}
+
+func TestTokenEntry_AllowsChat(t *testing.T) {
+	unrestricted := TokenEntry{}
+	if !unrestricted.allowsChat("123@s.whatsapp.net") {
+		t.Fatalf("empty AllowedChats should allow every chat")
+	}
+	if !unrestricted.allowsChat("") {
+		t.Fatalf("a request with no chat scoping should always be allowed")
+	}
+
+	scoped := TokenEntry{AllowedChats: []string{"123@s.whatsapp.net"}}
+	if !scoped.allowsChat("123@s.whatsapp.net") {
+		t.Fatalf("expected scoped token to allow its own chat")
+	}
+	if scoped.allowsChat("456@s.whatsapp.net") {
+		t.Fatalf("expected scoped token to reject a different chat")
+	}
+}
+
+func TestTokenEntry_AllowsChats(t *testing.T) {
+	scoped := TokenEntry{AllowedChats: []string{"a@s.whatsapp.net", "b@s.whatsapp.net"}}
+
+	if scoped.allowsChats(nil) {
+		t.Fatalf("a chat-scoped token must not be granted an unscoped (empty) subscribe request")
+	}
+	if !scoped.allowsChats([]string{"a@s.whatsapp.net"}) {
+		t.Fatalf("expected scoped token to allow a subset of its own chats")
+	}
+	if scoped.allowsChats([]string{"a@s.whatsapp.net", "c@s.whatsapp.net"}) {
+		t.Fatalf("expected scoped token to reject a request including a chat it's not scoped to")
+	}
+
+	unrestricted := TokenEntry{}
+	if !unrestricted.allowsChats(nil) {
+		t.Fatalf("an unrestricted token should allow an unscoped subscribe request")
+	}
+}
+
+func TestTokenStore_RevokeAndReload(t *testing.T) {
+	dir := t.TempDir()
+	ts, err := LoadTokenStore(dir)
+	if err != nil {
+		t.Fatalf("LoadTokenStore: %v", err)
+	}
+	if _, err := ts.Add("a", nil, nil); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if _, err := ts.Add("b", nil, nil); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	// other stands in for a long-running server's TokenStore: loaded before
+	// the revoke, it only sees it once Reload re-reads the file on disk.
+	other, err := LoadTokenStore(dir)
+	if err != nil {
+		t.Fatalf("LoadTokenStore: %v", err)
+	}
+
+	if err := ts.Revoke("a"); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	if err := ts.Revoke("a"); err == nil {
+		t.Fatalf("expected Revoke of an already-removed entry to error")
+	}
+
+	if err := other.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	names := map[string]bool{}
+	for _, e := range other.List() {
+		names[e.Name] = true
+	}
+	if names["a"] {
+		t.Fatalf("expected revoked entry %q to be gone after reload from disk", "a")
+	}
+	if !names["b"] {
+		t.Fatalf("expected entry %q to survive", "b")
+	}
+}
@@ -0,0 +1,16 @@
+package msgstore
+
+import "github.com/steipete/wacli/internal/store"
+
+// DBExporter is a no-op Exporter: the message is already in SQLite by the
+// time a Manager is invoked, so there is nothing additional to do. It exists
+// so "db" can be listed alongside "fs" in an exporter set without a special
+// case.
+type DBExporter struct{}
+
+// NewDBExporter returns the no-op DB exporter.
+func NewDBExporter() *DBExporter { return &DBExporter{} }
+
+func (*DBExporter) Export(store.Message) error { return nil }
+
+func (*DBExporter) Close() error { return nil }
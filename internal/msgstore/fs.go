@@ -0,0 +1,73 @@
+package msgstore
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/steipete/wacli/internal/store"
+)
+
+// FSExporter mirrors messages into <baseDir>/logs/<chat-jid>/YYYY-MM-DD.log,
+// one line per message, so an append-only audit trail survives independent
+// of the SQLite store.
+type FSExporter struct {
+	baseDir string
+
+	mu    sync.Mutex
+	files map[string]*os.File // "chatJID/YYYY-MM-DD" -> open handle
+}
+
+// NewFSExporter creates an exporter rooted at <storeDir>/logs.
+func NewFSExporter(storeDir string) *FSExporter {
+	return &FSExporter{
+		baseDir: filepath.Join(storeDir, "logs"),
+		files:   make(map[string]*os.File),
+	}
+}
+
+func (e *FSExporter) Export(msg store.Message) error {
+	if msg.ChatJID == "" {
+		return fmt.Errorf("export message: chat_jid is required")
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	day := msg.Timestamp.UTC().Format("2006-01-02")
+	key := msg.ChatJID + "/" + day
+
+	f, ok := e.files[key]
+	if !ok {
+		dir := filepath.Join(e.baseDir, msg.ChatJID)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("create log dir: %w", err)
+		}
+		var err error
+		f, err = os.OpenFile(filepath.Join(dir, day+".log"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return fmt.Errorf("open log file: %w", err)
+		}
+		e.files[key] = f
+	}
+
+	if _, err := f.WriteString(encodeLogLine(msg) + "\n"); err != nil {
+		return fmt.Errorf("write log line: %w", err)
+	}
+	return nil
+}
+
+func (e *FSExporter) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var firstErr error
+	for key, f := range e.files {
+		if err := f.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(e.files, key)
+	}
+	return firstErr
+}
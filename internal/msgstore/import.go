@@ -0,0 +1,103 @@
+package msgstore
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steipete/wacli/internal/store"
+)
+
+// ImportFS parses the fs exporter's on-disk log tree rooted at
+// <storeDir>/logs and backfills the messages/chats tables in db. It is
+// idempotent: UpsertMessage conflicts on (chat_jid, msg_id), so re-running
+// an import only updates existing rows rather than duplicating them.
+func ImportFS(storeDir string, db *store.DB) (int, error) {
+	baseDir := filepath.Join(storeDir, "logs")
+	entries, err := os.ReadDir(baseDir)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("read logs dir: %w", err)
+	}
+
+	n := 0
+	for _, chatDir := range entries {
+		if !chatDir.IsDir() {
+			continue
+		}
+		chatJID := chatDir.Name()
+		if err := db.UpsertChat(chatJID, chatKindFromJID(chatJID), "", time.Time{}); err != nil {
+			return n, fmt.Errorf("upsert chat %s: %w", chatJID, err)
+		}
+
+		logFiles, err := os.ReadDir(filepath.Join(baseDir, chatJID))
+		if err != nil {
+			return n, fmt.Errorf("read chat log dir %s: %w", chatJID, err)
+		}
+		for _, lf := range logFiles {
+			if lf.IsDir() || !strings.HasSuffix(lf.Name(), ".log") {
+				continue
+			}
+			imported, err := importLogFile(filepath.Join(baseDir, chatJID, lf.Name()), chatJID, db)
+			if err != nil {
+				return n, fmt.Errorf("import %s/%s: %w", chatJID, lf.Name(), err)
+			}
+			n += imported
+		}
+	}
+	return n, nil
+}
+
+func importLogFile(path, chatJID string, db *store.DB) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	n := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		msg, err := decodeLogLine(chatJID, line)
+		if err != nil {
+			return n, err
+		}
+		if err := db.UpsertMessage(store.UpsertMessageParams{
+			ChatJID:    msg.ChatJID,
+			MsgID:      msg.MsgID,
+			SenderJID:  msg.SenderJID,
+			SenderName: msg.SenderName,
+			Timestamp:  msg.Timestamp,
+			FromMe:     msg.FromMe,
+			Text:       msg.Text,
+		}); err != nil {
+			return n, fmt.Errorf("upsert message %s: %w", msg.MsgID, err)
+		}
+		n++
+	}
+	return n, scanner.Err()
+}
+
+// chatKindFromJID guesses a chat kind from its JID domain, mirroring the
+// heuristic used when chats are first seen during sync.
+func chatKindFromJID(jid string) string {
+	switch {
+	case strings.HasSuffix(jid, "@g.us"):
+		return "group"
+	case strings.HasSuffix(jid, "@broadcast"):
+		return "broadcast"
+	case strings.HasSuffix(jid, "@s.whatsapp.net"):
+		return "dm"
+	default:
+		return "unknown"
+	}
+}
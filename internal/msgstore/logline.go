@@ -0,0 +1,68 @@
+package msgstore
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steipete/wacli/internal/store"
+)
+
+// logLineFields is the number of tab-separated columns used by both the fs
+// exporter and the logs migrate importer. msg_id is included (even though
+// it makes the line a little less prose-like) so re-imports can be
+// idempotent.
+const logLineFields = 7
+
+// encodeLogLine renders msg as one grep-able, tab-separated line.
+func encodeLogLine(msg store.Message) string {
+	mediaRef := msg.MediaType
+	if msg.MediaCaption != "" {
+		mediaRef = mediaRef + ":" + msg.MediaCaption
+	}
+	return strings.Join([]string{
+		msg.Timestamp.UTC().Format(time.RFC3339),
+		msg.MsgID,
+		strconv.FormatBool(msg.FromMe),
+		msg.SenderJID,
+		sanitizeField(msg.SenderName),
+		sanitizeField(msg.Text),
+		sanitizeField(mediaRef),
+	}, "\t")
+}
+
+// decodeLogLine parses one line written by encodeLogLine back into the
+// fields needed to backfill a messages row. chatJID comes from the
+// directory the line was read from, since it is not repeated per line.
+func decodeLogLine(chatJID, line string) (store.Message, error) {
+	parts := strings.SplitN(line, "\t", logLineFields)
+	if len(parts) != logLineFields {
+		return store.Message{}, fmt.Errorf("malformed log line: want %d tab-separated fields, got %d", logLineFields, len(parts))
+	}
+	ts, err := time.Parse(time.RFC3339, parts[0])
+	if err != nil {
+		return store.Message{}, fmt.Errorf("parse timestamp: %w", err)
+	}
+	fromMe, err := strconv.ParseBool(parts[2])
+	if err != nil {
+		return store.Message{}, fmt.Errorf("parse from_me: %w", err)
+	}
+	mediaType, _, _ := strings.Cut(parts[6], ":")
+	return store.Message{
+		ChatJID:    chatJID,
+		MsgID:      parts[1],
+		FromMe:     fromMe,
+		SenderJID:  parts[3],
+		SenderName: parts[4],
+		Text:       parts[5],
+		MediaType:  mediaType,
+		Timestamp:  ts,
+	}, nil
+}
+
+func sanitizeField(s string) string {
+	s = strings.ReplaceAll(s, "\t", " ")
+	s = strings.ReplaceAll(s, "\n", " ")
+	return s
+}
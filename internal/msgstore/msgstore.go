@@ -0,0 +1,52 @@
+// Package msgstore mirrors messages written to the SQLite store into
+// additional archives, so that a grep-able history survives even if the
+// queryable DB is lost or needs to be rebuilt.
+package msgstore
+
+import (
+	"github.com/steipete/wacli/internal/store"
+)
+
+// Exporter receives every message as it is written to the store. Exporters
+// are best-effort: a failing exporter should not fail the write to the
+// primary store, so Manager.Export hands the first error back to its
+// caller, which logs it rather than failing the write.
+type Exporter interface {
+	// Export appends or mirrors msg into the exporter's archive.
+	Export(msg store.Message) error
+	// Close releases any resources held by the exporter.
+	Close() error
+}
+
+// Manager fans writes out to a set of configured exporters.
+type Manager struct {
+	exporters []Exporter
+}
+
+// NewManager creates a Manager that fans out to the given exporters in order.
+func NewManager(exporters ...Exporter) *Manager {
+	return &Manager{exporters: exporters}
+}
+
+// Export sends msg to every configured exporter, collecting (but not
+// stopping on) the first error so a bad exporter cannot block the others.
+func (m *Manager) Export(msg store.Message) error {
+	var firstErr error
+	for _, e := range m.exporters {
+		if err := e.Export(msg); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Close closes every configured exporter, returning the first error.
+func (m *Manager) Close() error {
+	var firstErr error
+	for _, e := range m.exporters {
+		if err := e.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
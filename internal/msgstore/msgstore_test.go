@@ -0,0 +1,120 @@
+package msgstore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steipete/wacli/internal/store"
+)
+
+func TestFSExporter_AppendsWithoutDuplicating(t *testing.T) {
+	dir := t.TempDir()
+	e := NewFSExporter(dir)
+	defer e.Close()
+
+	ts := time.Date(2026, 7, 29, 12, 0, 0, 0, time.UTC)
+	msg := store.Message{ChatJID: "123@s.whatsapp.net", MsgID: "m1", Timestamp: ts, Text: "hi"}
+
+	if err := e.Export(msg); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	msg.MsgID = "m2"
+	if err := e.Export(msg); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	logPath := filepath.Join(dir, "logs", "123@s.whatsapp.net", "2026-07-29.log")
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("read log file: %v", err)
+	}
+	lines := splitNonEmptyLines(string(data))
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines from 2 exports to the same day, got %d: %q", len(lines), data)
+	}
+}
+
+func TestFSExporter_RequiresChatJID(t *testing.T) {
+	e := NewFSExporter(t.TempDir())
+	defer e.Close()
+
+	if err := e.Export(store.Message{MsgID: "m1"}); err == nil {
+		t.Fatalf("expected Export to reject a message with no ChatJID")
+	}
+}
+
+func TestManager_FansOutToAllExporters(t *testing.T) {
+	dir := t.TempDir()
+	mgr := NewManager(NewFSExporter(dir), NewDBExporter())
+	defer mgr.Close()
+
+	msg := store.Message{ChatJID: "123@s.whatsapp.net", MsgID: "m1", Timestamp: time.Now().UTC(), Text: "hi"}
+	if err := mgr.Export(msg); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, "logs", "123@s.whatsapp.net"))
+	if err != nil {
+		t.Fatalf("read chat log dir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected the fs exporter to have written one day-log, found %d entries", len(entries))
+	}
+}
+
+func TestEncodeDecodeLogLine_RoundTrip(t *testing.T) {
+	msg := store.Message{
+		ChatJID:    "123@s.whatsapp.net",
+		MsgID:      "m1",
+		SenderJID:  "456@s.whatsapp.net",
+		SenderName: "Max",
+		FromMe:     true,
+		Text:       "hello\tworld",
+		MediaType:  "image",
+	}
+	line := encodeLogLine(msg)
+
+	got, err := decodeLogLine(msg.ChatJID, line)
+	if err != nil {
+		t.Fatalf("decodeLogLine: %v", err)
+	}
+	if got.MsgID != msg.MsgID || got.SenderJID != msg.SenderJID || got.FromMe != msg.FromMe || got.MediaType != msg.MediaType {
+		t.Fatalf("round-trip mismatch: got %+v, want fields from %+v", got, msg)
+	}
+	if got.Text == msg.Text {
+		t.Fatalf("expected embedded tab in Text to be sanitized, not preserved verbatim")
+	}
+}
+
+func TestChatKindFromJID(t *testing.T) {
+	cases := map[string]string{
+		"123@g.us":            "group",
+		"123@broadcast":       "broadcast",
+		"123@s.whatsapp.net":  "dm",
+		"123@unknown.example": "unknown",
+	}
+	for jid, want := range cases {
+		if got := chatKindFromJID(jid); got != want {
+			t.Errorf("chatKindFromJID(%q) = %q, want %q", jid, got, want)
+		}
+	}
+}
+
+func splitNonEmptyLines(s string) []string {
+	var out []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\n' {
+			if i > start {
+				out = append(out, s[start:i])
+			}
+			start = i + 1
+		}
+	}
+	if start < len(s) {
+		out = append(out, s[start:])
+	}
+	return out
+}
@@ -0,0 +1,65 @@
+package provisioning
+
+import (
+	"sync"
+	"time"
+)
+
+// Event is a single item on the provisioning event stream, delivered to
+// WebSocket subscribers as newline-delimited JSON.
+type Event struct {
+	Type      string    `json:"type"` // "connection_state", or a wa.IncomingEvent type ("message", "receipt", "presence", ...)
+	Timestamp time.Time `json:"timestamp"`
+	Data      any       `json:"data,omitempty"`
+}
+
+// EventBus fans Publish calls out to every currently-subscribed WebSocket
+// connection. It mirrors the bridge-style event stream mautrix-whatsapp's
+// provisioning API exposes to dashboards/mobile clients.
+type EventBus struct {
+	mu     sync.Mutex
+	nextID int
+	subs   map[int]chan Event
+}
+
+// NewEventBus returns an empty bus.
+func NewEventBus() *EventBus {
+	return &EventBus{subs: make(map[int]chan Event)}
+}
+
+// Subscribe registers a new listener and returns its id (for Unsubscribe)
+// and a channel that receives every Event published from here on. The
+// channel is buffered; a slow subscriber drops events rather than blocking
+// Publish for everyone else.
+func (b *EventBus) Subscribe() (int, <-chan Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := b.nextID
+	b.nextID++
+	ch := make(chan Event, 32)
+	b.subs[id] = ch
+	return id, ch
+}
+
+// Unsubscribe removes a listener and closes its channel.
+func (b *EventBus) Unsubscribe(id int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if ch, ok := b.subs[id]; ok {
+		close(ch)
+		delete(b.subs, id)
+	}
+}
+
+// Publish delivers evt to every current subscriber, dropping it for any
+// subscriber whose buffer is full.
+func (b *EventBus) Publish(evt Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, ch := range b.subs {
+		select {
+		case ch <- evt:
+		default:
+		}
+	}
+}
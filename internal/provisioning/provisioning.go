@@ -0,0 +1,207 @@
+// Package provisioning exposes the same operations as internal/ipc over
+// HTTP and WebSocket, modeled on mautrix-whatsapp's provisioning API, so
+// wacli can be driven by non-Go clients (mobile apps, browser dashboards,
+// home automation) without opening a Unix socket.
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/steipete/wacli/internal/ipc"
+)
+
+// Server runs the HTTP/WebSocket provisioning API alongside ipc.Server,
+// against the same Handler.
+type Server struct {
+	addr    string
+	token   string
+	handler ipc.Handler
+	bus     *EventBus
+
+	httpServer *http.Server
+}
+
+// NewServer returns a provisioning server listening on addr. token is the
+// bearer token every request (including the WebSocket upgrade) must
+// present; an empty token disables authentication, which is only sensible
+// for addr bound to loopback. Callers driving addr/token from user-facing
+// flags (see cmd/wacli's --http-listen/--http-token) should refuse to start
+// with an empty token on a non-loopback addr rather than rely on this
+// package to enforce it.
+func NewServer(addr, token string, handler ipc.Handler, bus *EventBus) *Server {
+	s := &Server{addr: addr, token: token, handler: handler, bus: bus}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/ping", s.authed(s.handlePing))
+	mux.HandleFunc("/api/send_text", s.authed(s.handleSendText))
+	mux.HandleFunc("/api/delete_message", s.authed(s.handleDeleteMessage))
+	mux.HandleFunc("/api/chat_state", s.authed(s.handleChatState))
+	mux.HandleFunc("/api/status", s.authed(s.handleStatus))
+	mux.HandleFunc("/api/events", s.authed(s.handleEvents))
+
+	s.httpServer = &http.Server{Addr: addr, Handler: mux}
+	return s
+}
+
+// Start begins serving in the background. It returns once the listener is
+// confirmed to have bound the address: net.Listen itself is synchronous, so
+// a taken port or permission error surfaces here rather than being papered
+// over by a fixed sleep, mirroring ipc.Server.Start's behavior.
+func (s *Server) Start() error {
+	ln, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("provisioning API: %w", err)
+	}
+	go func() {
+		_ = s.httpServer.Serve(ln)
+	}()
+	return nil
+}
+
+// Stop gracefully shuts the server down.
+func (s *Server) Stop(ctx context.Context) error {
+	return s.httpServer.Shutdown(ctx)
+}
+
+func (s *Server) authed(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.token != "" {
+			auth := r.Header.Get("Authorization")
+			if auth != "Bearer "+s.token {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+		next(w, r)
+	}
+}
+
+func (s *Server) handlePing(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, ipc.Response{Success: true, Data: "pong"})
+}
+
+func (s *Server) handleSendText(w http.ResponseWriter, r *http.Request) {
+	var req ipc.Request
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.To == "" || req.Message == "" {
+		writeJSON(w, http.StatusBadRequest, ipc.Response{Success: false, Error: "to and message are required"})
+		return
+	}
+	msgID, err := s.handler.SendText(req.To, req.Message)
+	if err != nil {
+		writeJSON(w, http.StatusOK, ipc.Response{Success: false, Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, ipc.Response{Success: true, Data: ipc.SendTextResult{To: req.To, MsgID: msgID}})
+}
+
+func (s *Server) handleDeleteMessage(w http.ResponseWriter, r *http.Request) {
+	var req ipc.Request
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.Chat == "" || req.MsgID == "" {
+		writeJSON(w, http.StatusBadRequest, ipc.Response{Success: false, Error: "chat and msg_id are required"})
+		return
+	}
+	if err := s.handler.DeleteMessage(req.Chat, req.MsgID, req.ForEveryone); err != nil {
+		writeJSON(w, http.StatusOK, ipc.Response{Success: false, Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, ipc.Response{Success: true, Data: map[string]any{
+		"deleted":      true,
+		"chat":         req.Chat,
+		"msg_id":       req.MsgID,
+		"for_everyone": req.ForEveryone,
+	}})
+}
+
+func (s *Server) handleChatState(w http.ResponseWriter, r *http.Request) {
+	var req ipc.Request
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.Chat == "" || req.Action == "" {
+		writeJSON(w, http.StatusBadRequest, ipc.Response{Success: false, Error: "chat and action are required"})
+		return
+	}
+	if err := s.handler.ChatState(req.Chat, req.Action, req.Duration); err != nil {
+		writeJSON(w, http.StatusOK, ipc.Response{Success: false, Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, ipc.Response{Success: true, Data: map[string]any{
+		"action": req.Action,
+		"jid":    req.Chat,
+		"ok":     true,
+	}})
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	st, err := s.handler.Status()
+	if err != nil {
+		writeJSON(w, http.StatusOK, ipc.Response{Success: false, Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, ipc.Response{Success: true, Data: st})
+}
+
+// handleEvents upgrades the request to a WebSocket and streams newline-
+// delimited JSON Events to the client until it disconnects.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgradeWebSocket(w, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer conn.Close()
+
+	id, events := s.bus.Subscribe()
+	defer s.bus.Unsubscribe(id)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = conn.readLoop()
+	}()
+
+	for {
+		select {
+		case <-done:
+			return
+		case evt, ok := <-events:
+			if !ok {
+				return
+			}
+			payload, err := json.Marshal(evt)
+			if err != nil {
+				continue
+			}
+			payload = append(payload, '\n')
+			if err := conn.WriteText(payload); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	defer r.Body.Close()
+	dec := json.NewDecoder(r.Body)
+	if err := dec.Decode(v); err != nil {
+		writeJSON(w, http.StatusBadRequest, ipc.Response{Success: false, Error: fmt.Sprintf("invalid request body: %v", err)})
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, resp ipc.Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,202 @@
+package provisioning
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// websocketGUID is the fixed key-mixing GUID from RFC 6455 section 1.3.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+const (
+	wsOpText  = 0x1
+	wsOpClose = 0x8
+	wsOpPing  = 0x9
+	wsOpPong  = 0xA
+)
+
+// wsConn is a minimal RFC 6455 server-side WebSocket connection: enough to
+// push unfragmented text frames to the client and notice a close. wacli has
+// no third-party WebSocket dependency, so the handshake and framing are
+// implemented directly against net/http's Hijacker.
+type wsConn struct {
+	conn net.Conn
+	buf  *bufio.ReadWriter
+
+	// writeMu serializes writeFrame: it's called both from the event-publish
+	// loop (WriteText) and from readLoop's pong responder, and concurrent
+	// writes to the same bufio.Writer would interleave frame bytes.
+	writeMu sync.Mutex
+}
+
+// upgradeWebSocket performs the RFC 6455 handshake over an HTTP request and
+// hands back the hijacked connection framed for text messages. The caller
+// owns the returned wsConn and must Close it.
+func upgradeWebSocket(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		return nil, errors.New("not a websocket upgrade request")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		return nil, errors.New("missing Sec-WebSocket-Key")
+	}
+
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, errors.New("response writer does not support hijacking")
+	}
+	conn, buf, err := hj.Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("hijack: %w", err)
+	}
+
+	accept := acceptKey(key)
+	resp := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + accept + "\r\n\r\n"
+	if _, err := buf.WriteString(resp); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("write handshake: %w", err)
+	}
+	if err := buf.Flush(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("flush handshake: %w", err)
+	}
+
+	return &wsConn{conn: conn, buf: buf}, nil
+}
+
+func acceptKey(clientKey string) string {
+	h := sha1.New()
+	h.Write([]byte(clientKey))
+	h.Write([]byte(websocketGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// Close closes the underlying connection.
+func (c *wsConn) Close() error {
+	return c.conn.Close()
+}
+
+// WriteText sends an unfragmented, unmasked text frame (server-to-client
+// frames must not be masked per RFC 6455 section 5.1).
+func (c *wsConn) WriteText(payload []byte) error {
+	return c.writeFrame(wsOpText, payload)
+}
+
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	var header []byte
+	header = append(header, 0x80|opcode) // FIN + opcode
+
+	n := len(payload)
+	switch {
+	case n <= 125:
+		header = append(header, byte(n))
+	case n <= 0xFFFF:
+		header = append(header, 126)
+		ext := make([]byte, 2)
+		binary.BigEndian.PutUint16(ext, uint16(n))
+		header = append(header, ext...)
+	default:
+		header = append(header, 127)
+		ext := make([]byte, 8)
+		binary.BigEndian.PutUint64(ext, uint64(n))
+		header = append(header, ext...)
+	}
+
+	if _, err := c.buf.Write(header); err != nil {
+		return err
+	}
+	if _, err := c.buf.Write(payload); err != nil {
+		return err
+	}
+	return c.buf.Flush()
+}
+
+// readLoop blocks reading client frames until a close frame, an error, or
+// the connection drops. wacli's event stream is server-push only, so
+// readLoop exists purely to detect disconnects (and answer pings); any text
+// frames the client sends are discarded.
+func (c *wsConn) readLoop() error {
+	for {
+		opcode, payload, err := c.readFrame()
+		if err != nil {
+			return err
+		}
+		switch opcode {
+		case wsOpClose:
+			return errors.New("client closed connection")
+		case wsOpPing:
+			if err := c.writeFrame(wsOpPong, payload); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (c *wsConn) readFrame() (byte, []byte, error) {
+	head := make([]byte, 2)
+	if _, err := fillBuf(c.buf, head); err != nil {
+		return 0, nil, err
+	}
+	opcode := head[0] & 0x0F
+	masked := head[1]&0x80 != 0
+	length := uint64(head[1] & 0x7F)
+
+	switch length {
+	case 126:
+		ext := make([]byte, 2)
+		if _, err := fillBuf(c.buf, ext); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext))
+	case 127:
+		ext := make([]byte, 8)
+		if _, err := fillBuf(c.buf, ext); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext)
+	}
+
+	var maskKey [4]byte
+	if masked {
+		if _, err := fillBuf(c.buf, maskKey[:]); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	payload := make([]byte, length)
+	if _, err := fillBuf(c.buf, payload); err != nil {
+		return 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= maskKey[i%4]
+		}
+	}
+	return opcode, payload, nil
+}
+
+func fillBuf(buf *bufio.ReadWriter, dst []byte) (int, error) {
+	n := 0
+	for n < len(dst) {
+		m, err := buf.Read(dst[n:])
+		n += m
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,64 @@
+package provisioning
+
+import (
+	"bufio"
+	"net"
+	"sync"
+	"testing"
+)
+
+func newPipeConn(side net.Conn) *wsConn {
+	return &wsConn{
+		conn: side,
+		buf:  bufio.NewReadWriter(bufio.NewReader(side), bufio.NewWriter(side)),
+	}
+}
+
+// TestWsConn_WriteFrame_ConcurrentWritesDontInterleave is a regression test
+// for writeFrame being called concurrently from the event-publish loop
+// (WriteText) and readLoop's pong responder: without writeMu, two goroutines
+// could interleave their header+payload writes on the shared bufio.Writer,
+// corrupting the frame stream. Run with -race to catch the unsynchronized
+// case directly.
+func TestWsConn_WriteFrame_ConcurrentWritesDontInterleave(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+
+	c := newPipeConn(server)
+	reader := newPipeConn(client)
+
+	const writers = 8
+	const perWriter = 20
+	payload := []byte("0123456789")
+
+	var wg sync.WaitGroup
+	wg.Add(writers)
+	for i := 0; i < writers; i++ {
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perWriter; j++ {
+				if err := c.writeFrame(wsOpText, payload); err != nil {
+					t.Errorf("writeFrame: %v", err)
+					return
+				}
+			}
+		}()
+	}
+
+	total := writers * perWriter
+	for i := 0; i < total; i++ {
+		opcode, got, err := reader.readFrame()
+		if err != nil {
+			t.Fatalf("readFrame %d: %v", i, err)
+		}
+		if opcode != wsOpText {
+			t.Fatalf("readFrame %d: opcode = %#x, want %#x", i, opcode, wsOpText)
+		}
+		if string(got) != string(payload) {
+			t.Fatalf("readFrame %d: payload = %q, want %q (frames interleaved)", i, got, payload)
+		}
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,150 @@
+package store
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// chatCursor is the decoded form of an opaque ChatHistory* cursor: a
+// (ts, rowid) keyset position into idx_messages_chat_ts. Ties on ts are
+// broken by rowid so paging is deterministic even when many messages share
+// a timestamp.
+type chatCursor struct {
+	Timestamp int64 `json:"ts"`
+	RowID     int64 `json:"rowid"`
+}
+
+func encodeChatCursor(ts, rowid int64) string {
+	b, _ := json.Marshal(chatCursor{Timestamp: ts, RowID: rowid})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeChatCursor(cursor string) (chatCursor, error) {
+	var c chatCursor
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return c, fmt.Errorf("decode cursor: %w", err)
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, fmt.Errorf("parse cursor: %w", err)
+	}
+	return c, nil
+}
+
+// ChatHistoryPage is one page of a cursor-paginated chat history query.
+type ChatHistoryPage struct {
+	Messages   []Message
+	NextCursor string // pass as --before/--after to continue in the same direction; empty once exhausted
+	PrevCursor string // cursor for the opposite direction, pointing back at this page's first message
+}
+
+// ChatHistoryBefore returns up to limit messages in chatJID strictly older
+// than cursor (or the newest messages if cursor is empty), newest-first.
+// It drives the keyset predicate off idx_messages_chat_ts rather than OFFSET.
+func (d *DB) ChatHistoryBefore(chatJID, cursor string, limit int) (ChatHistoryPage, error) {
+	q := `SELECT ` + historyColumns + ` FROM messages WHERE chat_jid = ?`
+	args := []interface{}{chatJID}
+	if cursor != "" {
+		c, err := decodeChatCursor(cursor)
+		if err != nil {
+			return ChatHistoryPage{}, err
+		}
+		q += ` AND (ts < ? OR (ts = ? AND rowid < ?))`
+		args = append(args, c.Timestamp, c.Timestamp, c.RowID)
+	}
+	q += ` ORDER BY ts DESC, rowid DESC LIMIT ?`
+	args = append(args, limit+1)
+	return d.chatHistoryPage(q, args, limit)
+}
+
+// ChatHistoryAfter returns up to limit messages in chatJID strictly newer
+// than cursor, oldest-first.
+func (d *DB) ChatHistoryAfter(chatJID, cursor string, limit int) (ChatHistoryPage, error) {
+	c, err := decodeChatCursor(cursor)
+	if err != nil {
+		return ChatHistoryPage{}, err
+	}
+	q := `SELECT ` + historyColumns + ` FROM messages WHERE chat_jid = ? AND (ts > ? OR (ts = ? AND rowid > ?))
+	      ORDER BY ts ASC, rowid ASC LIMIT ?`
+	return d.chatHistoryPage(q, []interface{}{chatJID, c.Timestamp, c.Timestamp, c.RowID, limit + 1}, limit)
+}
+
+// ChatHistoryLatest returns the most recent limit messages in chatJID.
+func (d *DB) ChatHistoryLatest(chatJID string, limit int) (ChatHistoryPage, error) {
+	return d.ChatHistoryBefore(chatJID, "", limit)
+}
+
+// ChatHistoryAround returns messages surrounding cursor, oldest-first.
+func (d *DB) ChatHistoryAround(chatJID, cursor string, limit int) (ChatHistoryPage, error) {
+	c, err := decodeChatCursor(cursor)
+	if err != nil {
+		return ChatHistoryPage{}, err
+	}
+	half := limit / 2
+
+	before, err := d.chatHistoryPage(
+		`SELECT `+historyColumns+` FROM messages WHERE chat_jid = ? AND (ts < ? OR (ts = ? AND rowid < ?)) ORDER BY ts DESC, rowid DESC LIMIT ?`,
+		[]interface{}{chatJID, c.Timestamp, c.Timestamp, c.RowID, half + 1}, half,
+	)
+	if err != nil {
+		return ChatHistoryPage{}, err
+	}
+	anchor, err := d.chatHistoryPage(
+		`SELECT `+historyColumns+` FROM messages WHERE chat_jid = ? AND ts = ? AND rowid = ?`,
+		[]interface{}{chatJID, c.Timestamp, c.RowID}, 1,
+	)
+	if err != nil {
+		return ChatHistoryPage{}, err
+	}
+	after, err := d.chatHistoryPage(
+		`SELECT `+historyColumns+` FROM messages WHERE chat_jid = ? AND (ts > ? OR (ts = ? AND rowid > ?)) ORDER BY ts ASC, rowid ASC LIMIT ?`,
+		[]interface{}{chatJID, c.Timestamp, c.Timestamp, c.RowID, limit - half + 1}, limit-half,
+	)
+	if err != nil {
+		return ChatHistoryPage{}, err
+	}
+
+	out := make([]Message, 0, len(before.Messages)+len(anchor.Messages)+len(after.Messages))
+	for i := len(before.Messages) - 1; i >= 0; i-- {
+		out = append(out, before.Messages[i])
+	}
+	out = append(out, anchor.Messages...)
+	out = append(out, after.Messages...)
+
+	page := ChatHistoryPage{Messages: out, PrevCursor: before.NextCursor, NextCursor: after.NextCursor}
+	return page, nil
+}
+
+// ChatHistoryBetween returns up to limit messages in chatJID with ts in
+// [start, end], oldest-first.
+func (d *DB) ChatHistoryBetween(chatJID string, start, end time.Time, limit int) (ChatHistoryPage, error) {
+	q := `SELECT ` + historyColumns + ` FROM messages WHERE chat_jid = ? AND ts >= ? AND ts <= ? ORDER BY ts ASC, rowid ASC LIMIT ?`
+	return d.chatHistoryPage(q, []interface{}{chatJID, unix(start), unix(end), limit + 1}, limit)
+}
+
+// chatHistoryPage runs q (whose final LIMIT arg is expected to be limit+1,
+// one more row than the caller asked for) and trims back to limit, using the
+// presence of that extra row to decide whether NextCursor should be emitted:
+// it is left empty once the result set is exhausted, rather than unconditionally
+// pointing one query past the last row.
+func (d *DB) chatHistoryPage(q string, args []interface{}, limit int) (ChatHistoryPage, error) {
+	msgs, err := d.queryHistory(q, args...)
+	if err != nil {
+		return ChatHistoryPage{}, err
+	}
+	hasMore := len(msgs) > limit
+	if hasMore {
+		msgs = msgs[:limit]
+	}
+	page := ChatHistoryPage{Messages: msgs}
+	if len(msgs) > 0 {
+		first, last := msgs[0], msgs[len(msgs)-1]
+		page.PrevCursor = encodeChatCursor(unix(first.Timestamp), first.RowID)
+		if hasMore {
+			page.NextCursor = encodeChatCursor(unix(last.Timestamp), last.RowID)
+		}
+	}
+	return page, nil
+}
@@ -0,0 +1,76 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AddChatLabel attaches label (with an optional display color) to jid.
+// sortOrder controls its position among jid's other labels (lower first);
+// existing labels keep their sortOrder unless explicitly re-added.
+func (d *DB) AddChatLabel(jid, label, color string, sortOrder int) error {
+	label = strings.TrimSpace(label)
+	if label == "" {
+		return fmt.Errorf("label is required")
+	}
+	now := time.Now().UTC().Unix()
+	_, err := d.sql.Exec(`
+		INSERT INTO chat_labels(jid, label, color, sort_order, updated_at) VALUES(?, ?, NULLIF(?, ''), ?, ?)
+		ON CONFLICT(jid, label) DO UPDATE SET color=excluded.color, sort_order=excluded.sort_order, updated_at=excluded.updated_at
+	`, jid, label, color, sortOrder, now)
+	return err
+}
+
+// RemoveChatLabel detaches label from jid.
+func (d *DB) RemoveChatLabel(jid, label string) error {
+	_, err := d.sql.Exec(`DELETE FROM chat_labels WHERE jid = ? AND label = ?`, jid, label)
+	return err
+}
+
+// ListChatLabels returns jid's labels, ordered by sort_order then label.
+func (d *DB) ListChatLabels(jid string) ([]string, error) {
+	rows, err := d.sql.Query(`SELECT label FROM chat_labels WHERE jid = ? ORDER BY sort_order, label`, jid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var labels []string
+	for rows.Next() {
+		var label string
+		if err := rows.Scan(&label); err != nil {
+			return nil, err
+		}
+		labels = append(labels, label)
+	}
+	return labels, rows.Err()
+}
+
+// ListChatLabelsForJIDs returns jids' labels in one query, keyed by jid, so
+// callers listing many chats (e.g. `chats list`) don't issue one
+// ListChatLabels round-trip per row.
+func (d *DB) ListChatLabelsForJIDs(jids []string) (map[string][]string, error) {
+	out := make(map[string][]string, len(jids))
+	if len(jids) == 0 {
+		return out, nil
+	}
+	args := make([]interface{}, len(jids))
+	for i, jid := range jids {
+		args[i] = jid
+	}
+	rows, err := d.sql.Query(`SELECT jid, label FROM chat_labels WHERE jid IN (`+placeholders(len(jids))+`) ORDER BY jid, sort_order, label`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var jid, label string
+		if err := rows.Scan(&jid, &label); err != nil {
+			return nil, err
+		}
+		out[jid] = append(out[jid], label)
+	}
+	return out, rows.Err()
+}
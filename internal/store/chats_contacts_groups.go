@@ -13,20 +13,27 @@ type ChatListFilter struct {
 	Pinned   *bool
 	Muted    *bool
 	Unread   *bool
+	Tags     *[]string // match chats whose jid, or any group participant's jid, has any of these contact_tags
+	HasTag   *bool     // true: chat has at least one tag anywhere; false: chat has none
+	Labels   *[]string // match chats that carry any of these chat_labels
+	HasLabel *bool     // true: chat has at least one label; false: chat has none
 }
 
 func (d *DB) UpsertChat(jid, kind, name string, lastTS time.Time) error {
 	if strings.TrimSpace(kind) == "" {
 		kind = "unknown"
 	}
-	_, err := d.sql.Exec(`
+	drv := d.driver()
+	q := drv.Rebind(fmt.Sprintf(`
 		INSERT INTO chats(jid, kind, name, last_message_ts)
 		VALUES(?, ?, ?, ?)
-		ON CONFLICT(jid) DO UPDATE SET
-			kind=excluded.kind,
-			name=CASE WHEN excluded.name IS NOT NULL AND excluded.name != '' THEN excluded.name ELSE chats.name END,
-			last_message_ts=CASE WHEN excluded.last_message_ts > COALESCE(chats.last_message_ts, 0) THEN excluded.last_message_ts ELSE chats.last_message_ts END
-	`, jid, kind, name, unix(lastTS))
+		%s
+	`, drv.UpsertClause([]string{"jid"}, []string{
+		"kind=" + drv.Excluded("kind"),
+		"name=CASE WHEN " + drv.Excluded("name") + " IS NOT NULL AND " + drv.Excluded("name") + " != '' THEN " + drv.Excluded("name") + " ELSE " + drv.Old("chats", "name") + " END",
+		"last_message_ts=CASE WHEN " + drv.Excluded("last_message_ts") + " > COALESCE(" + drv.Old("chats", "last_message_ts") + ", 0) THEN " + drv.Excluded("last_message_ts") + " ELSE " + drv.Old("chats", "last_message_ts") + " END",
+	})))
+	_, err := d.sql.Exec(q, jid, kind, name, unix(lastTS))
 	return err
 }
 
@@ -62,8 +69,46 @@ func (d *DB) ListChats(f ChatListFilter) ([]Chat, error) {
 		q += ` AND unread = ?`
 		args = append(args, boolToInt(*f.Unread))
 	}
+	if f.Tags != nil && len(*f.Tags) > 0 {
+		q += ` AND EXISTS (
+			SELECT 1 FROM contact_tags t
+			WHERE t.tag IN (` + placeholders(len(*f.Tags)) + `)
+			  AND (t.jid = chats.jid OR t.jid IN (SELECT user_jid FROM group_participants WHERE group_jid = chats.jid))
+		)`
+		for _, tag := range *f.Tags {
+			args = append(args, tag)
+		}
+	}
+	if f.HasTag != nil {
+		exists := `EXISTS (
+			SELECT 1 FROM contact_tags t
+			WHERE t.jid = chats.jid OR t.jid IN (SELECT user_jid FROM group_participants WHERE group_jid = chats.jid)
+		)`
+		if *f.HasTag {
+			q += ` AND ` + exists
+		} else {
+			q += ` AND NOT ` + exists
+		}
+	}
+	if f.Labels != nil && len(*f.Labels) > 0 {
+		q += ` AND EXISTS (
+			SELECT 1 FROM chat_labels l WHERE l.jid = chats.jid AND l.label IN (` + placeholders(len(*f.Labels)) + `)
+		)`
+		for _, label := range *f.Labels {
+			args = append(args, label)
+		}
+	}
+	if f.HasLabel != nil {
+		exists := `EXISTS (SELECT 1 FROM chat_labels l WHERE l.jid = chats.jid)`
+		if *f.HasLabel {
+			q += ` AND ` + exists
+		} else {
+			q += ` AND NOT ` + exists
+		}
+	}
 	q += ` ORDER BY pinned DESC, last_message_ts DESC LIMIT ?`
 	args = append(args, f.Limit)
+	q = d.driver().Rebind(q)
 
 	rows, err := d.sql.Query(q, args...)
 	if err != nil {
@@ -203,33 +248,39 @@ func (d *DB) ListTags(jid string) ([]string, error) {
 
 func (d *DB) UpsertContact(jid, phone, pushName, fullName, firstName, businessName string) error {
 	now := time.Now().UTC().Unix()
-	_, err := d.sql.Exec(`
+	drv := d.driver()
+	q := drv.Rebind(fmt.Sprintf(`
 		INSERT INTO contacts(jid, phone, push_name, full_name, first_name, business_name, updated_at)
 		VALUES (?, ?, ?, ?, ?, ?, ?)
-		ON CONFLICT(jid) DO UPDATE SET
-			phone=COALESCE(NULLIF(excluded.phone,''), contacts.phone),
-			push_name=COALESCE(NULLIF(excluded.push_name,''), contacts.push_name),
-			full_name=COALESCE(NULLIF(excluded.full_name,''), contacts.full_name),
-			first_name=COALESCE(NULLIF(excluded.first_name,''), contacts.first_name),
-			business_name=COALESCE(NULLIF(excluded.business_name,''), contacts.business_name),
-			updated_at=excluded.updated_at
-	`, jid, phone, pushName, fullName, firstName, businessName, now)
+		%s
+	`, drv.UpsertClause([]string{"jid"}, []string{
+		"phone=COALESCE(NULLIF(" + drv.Excluded("phone") + ",''), " + drv.Old("contacts", "phone") + ")",
+		"push_name=COALESCE(NULLIF(" + drv.Excluded("push_name") + ",''), " + drv.Old("contacts", "push_name") + ")",
+		"full_name=COALESCE(NULLIF(" + drv.Excluded("full_name") + ",''), " + drv.Old("contacts", "full_name") + ")",
+		"first_name=COALESCE(NULLIF(" + drv.Excluded("first_name") + ",''), " + drv.Old("contacts", "first_name") + ")",
+		"business_name=COALESCE(NULLIF(" + drv.Excluded("business_name") + ",''), " + drv.Old("contacts", "business_name") + ")",
+		"updated_at=" + drv.Excluded("updated_at"),
+	})))
+	_, err := d.sql.Exec(q, jid, phone, pushName, fullName, firstName, businessName, now)
 	return err
 }
 
 func (d *DB) UpsertGroup(jid, name, ownerJID string, created time.Time, isParent bool, linkedParentJID string) error {
 	now := time.Now().UTC().Unix()
-	_, err := d.sql.Exec(`
+	drv := d.driver()
+	q := drv.Rebind(fmt.Sprintf(`
 		INSERT INTO groups(jid, name, owner_jid, created_ts, is_parent, linked_parent_jid, updated_at)
 		VALUES (?, ?, ?, ?, ?, ?, ?)
-		ON CONFLICT(jid) DO UPDATE SET
-			name=COALESCE(NULLIF(excluded.name,''), groups.name),
-			owner_jid=COALESCE(NULLIF(excluded.owner_jid,''), groups.owner_jid),
-			created_ts=COALESCE(NULLIF(excluded.created_ts,0), groups.created_ts),
-			is_parent=excluded.is_parent,
-			linked_parent_jid=COALESCE(NULLIF(excluded.linked_parent_jid,''), groups.linked_parent_jid),
-			updated_at=excluded.updated_at
-	`, jid, name, ownerJID, unix(created), isParent, linkedParentJID, now)
+		%s
+	`, drv.UpsertClause([]string{"jid"}, []string{
+		"name=COALESCE(NULLIF(" + drv.Excluded("name") + ",''), " + drv.Old("groups", "name") + ")",
+		"owner_jid=COALESCE(NULLIF(" + drv.Excluded("owner_jid") + ",''), " + drv.Old("groups", "owner_jid") + ")",
+		"created_ts=COALESCE(NULLIF(" + drv.Excluded("created_ts") + ",0), " + drv.Old("groups", "created_ts") + ")",
+		"is_parent=" + drv.Excluded("is_parent"),
+		"linked_parent_jid=COALESCE(NULLIF(" + drv.Excluded("linked_parent_jid") + ",''), " + drv.Old("groups", "linked_parent_jid") + ")",
+		"updated_at=" + drv.Excluded("updated_at"),
+	})))
+	_, err := d.sql.Exec(q, jid, name, ownerJID, unix(created), isParent, linkedParentJID, now)
 	return err
 }
 
@@ -244,10 +295,11 @@ func (d *DB) ReplaceGroupParticipants(groupJID string, participants []GroupParti
 		}
 	}()
 
-	if _, err = tx.Exec(`DELETE FROM group_participants WHERE group_jid = ?`, groupJID); err != nil {
+	drv := d.driver()
+	if _, err = tx.Exec(drv.Rebind(`DELETE FROM group_participants WHERE group_jid = ?`), groupJID); err != nil {
 		return err
 	}
-	stmt, err := tx.Prepare(`INSERT INTO group_participants(group_jid, user_jid, role, updated_at) VALUES(?, ?, ?, ?)`)
+	stmt, err := tx.Prepare(drv.Rebind(`INSERT INTO group_participants(group_jid, user_jid, role, updated_at) VALUES(?, ?, ?, ?)`))
 	if err != nil {
 		return err
 	}
@@ -306,16 +358,22 @@ func (d *DB) SetAlias(jid, alias string) error {
 		return fmt.Errorf("alias is required")
 	}
 	now := time.Now().UTC().Unix()
-	_, err := d.sql.Exec(`
+	drv := d.driver()
+	q := drv.Rebind(fmt.Sprintf(`
 		INSERT INTO contact_aliases(jid, alias, notes, updated_at)
 		VALUES (?, ?, NULL, ?)
-		ON CONFLICT(jid) DO UPDATE SET alias=excluded.alias, updated_at=excluded.updated_at
-	`, jid, alias, now)
+		%s
+	`, drv.UpsertClause([]string{"jid"}, []string{
+		"alias=" + drv.Excluded("alias"),
+		"updated_at=" + drv.Excluded("updated_at"),
+	})))
+	_, err := d.sql.Exec(q, jid, alias, now)
 	return err
 }
 
 func (d *DB) RemoveAlias(jid string) error {
-	_, err := d.sql.Exec(`DELETE FROM contact_aliases WHERE jid = ?`, jid)
+	drv := d.driver()
+	_, err := d.sql.Exec(drv.Rebind(`DELETE FROM contact_aliases WHERE jid = ?`), jid)
 	return err
 }
 
@@ -325,14 +383,19 @@ func (d *DB) AddTag(jid, tag string) error {
 		return fmt.Errorf("tag is required")
 	}
 	now := time.Now().UTC().Unix()
-	_, err := d.sql.Exec(`
+	drv := d.driver()
+	q := drv.Rebind(fmt.Sprintf(`
 		INSERT INTO contact_tags(jid, tag, updated_at) VALUES(?, ?, ?)
-		ON CONFLICT(jid, tag) DO UPDATE SET updated_at=excluded.updated_at
-	`, jid, tag, now)
+		%s
+	`, drv.UpsertClause([]string{"jid", "tag"}, []string{
+		"updated_at=" + drv.Excluded("updated_at"),
+	})))
+	_, err := d.sql.Exec(q, jid, tag, now)
 	return err
 }
 
 func (d *DB) RemoveTag(jid, tag string) error {
-	_, err := d.sql.Exec(`DELETE FROM contact_tags WHERE jid = ? AND tag = ?`, jid, tag)
+	drv := d.driver()
+	_, err := d.sql.Exec(drv.Rebind(`DELETE FROM contact_tags WHERE jid = ? AND tag = ?`), jid, tag)
 	return err
 }
@@ -0,0 +1,138 @@
+package store
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Driver abstracts the SQL dialect differences between the backends wacli can
+// store its state in. The store package itself still talks to SQLite via
+// d.sql for day-to-day operations; Driver exists so that tooling which needs
+// to address multiple backends at once (the store migrate command) can
+// rewrite placeholders and upsert syntax without duplicating every query.
+type Driver interface {
+	// Name is the dialect name as used in DSNs ("sqlite", "mysql", "postgres").
+	Name() string
+	// Rebind rewrites a query written with `?` positional placeholders into
+	// this dialect's placeholder syntax.
+	Rebind(query string) string
+	// UpsertClause returns the dialect's "insert, update on conflict" tail
+	// (everything after the VALUES(...) clause) given the conflict columns
+	// and the "col=expr" assignments to apply on conflict. assignments must
+	// be built with Excluded/Old, not a literal "excluded."/table-qualified
+	// reference, since MySQL has no excluded pseudo-table and forbids
+	// table-qualifying the pre-update row in ON DUPLICATE KEY UPDATE.
+	UpsertClause(conflictCols []string, assignments []string) string
+	// Excluded returns how to reference col's candidate (about-to-be-written)
+	// value inside an upsert assignment: "excluded.col" for SQLite/Postgres,
+	// "VALUES(col)" for MySQL.
+	Excluded(col string) string
+	// Old returns how to reference col's pre-update value, read from table,
+	// inside an upsert assignment: SQLite/Postgres can table-qualify it
+	// ("table.col"); MySQL's ON DUPLICATE KEY UPDATE treats a bare column
+	// name as the pre-update value and rejects table-qualifying it.
+	Old(table, col string) string
+}
+
+// DriverForScheme returns the Driver for a DSN scheme such as "sqlite",
+// "mysql", or "postgres"/"postgresql". The scheme is the part of the DSN
+// before the first ":" (e.g. "postgres://user@host/db" -> "postgres").
+func DriverForScheme(scheme string) (Driver, error) {
+	switch strings.ToLower(scheme) {
+	case "sqlite", "sqlite3", "":
+		return sqliteDriver{}, nil
+	case "mysql":
+		return mysqlDriver{}, nil
+	case "postgres", "postgresql":
+		return postgresDriver{}, nil
+	default:
+		return nil, fmt.Errorf("unknown store driver %q (want sqlite, mysql, or postgres)", scheme)
+	}
+}
+
+// driver returns the Driver for d's live connection. DB has no stored
+// driver/DSN field of its own yet (Open still hardcodes a sqlite3 connection),
+// so the dialect is derived from the database/sql driver d.sql is actually
+// registered against, keeping UpsertChat/UpsertContact/ListChats/
+// ReplaceGroupParticipants correct the day Open grows a --store-driver/
+// --store-dsn flag to pick something other than sqlite3.
+func (d *DB) driver() Driver {
+	name := fmt.Sprintf("%T", d.sql.Driver())
+	switch {
+	case strings.Contains(name, "postgres"), strings.Contains(name, "pgx"), strings.Contains(name, "pq."):
+		return postgresDriver{}
+	case strings.Contains(name, "mysql"):
+		return mysqlDriver{}
+	default:
+		return sqliteDriver{}
+	}
+}
+
+// SplitDSN splits a "scheme:rest" DSN into its scheme and the remainder, the
+// way `wacli store migrate --from sqlite:./wacli.db --to postgres://...`
+// addresses are written.
+func SplitDSN(dsn string) (scheme, rest string) {
+	i := strings.Index(dsn, ":")
+	if i < 0 {
+		return "", dsn
+	}
+	return dsn[:i], strings.TrimLeft(dsn[i+1:], "/")
+}
+
+type sqliteDriver struct{}
+
+func (sqliteDriver) Name() string { return "sqlite" }
+
+func (sqliteDriver) Rebind(query string) string { return query }
+
+func (sqliteDriver) UpsertClause(conflictCols, assignments []string) string {
+	return fmt.Sprintf("ON CONFLICT(%s) DO UPDATE SET %s", strings.Join(conflictCols, ","), strings.Join(assignments, ", "))
+}
+
+func (sqliteDriver) Excluded(col string) string { return "excluded." + col }
+
+func (sqliteDriver) Old(table, col string) string { return table + "." + col }
+
+type postgresDriver struct{}
+
+func (postgresDriver) Name() string { return "postgres" }
+
+var qMarkRe = regexp.MustCompile(`\?`)
+
+// Rebind rewrites sequential `?` placeholders into Postgres's `$1`, `$2`, ...
+func (postgresDriver) Rebind(query string) string {
+	n := 0
+	return qMarkRe.ReplaceAllStringFunc(query, func(string) string {
+		n++
+		return "$" + strconv.Itoa(n)
+	})
+}
+
+func (postgresDriver) UpsertClause(conflictCols, assignments []string) string {
+	return fmt.Sprintf("ON CONFLICT(%s) DO UPDATE SET %s", strings.Join(conflictCols, ","), strings.Join(assignments, ", "))
+}
+
+func (postgresDriver) Excluded(col string) string { return "excluded." + col }
+
+func (postgresDriver) Old(table, col string) string { return table + "." + col }
+
+type mysqlDriver struct{}
+
+func (mysqlDriver) Name() string { return "mysql" }
+
+func (mysqlDriver) Rebind(query string) string { return query }
+
+func (mysqlDriver) UpsertClause(_, assignments []string) string {
+	return "ON DUPLICATE KEY UPDATE " + strings.Join(assignments, ", ")
+}
+
+// Excluded references the candidate row via VALUES(col): MySQL has no
+// "excluded" pseudo-table (that's SQLite/Postgres upsert syntax).
+func (mysqlDriver) Excluded(col string) string { return "VALUES(" + col + ")" }
+
+// Old returns col unqualified: inside ON DUPLICATE KEY UPDATE, MySQL treats
+// a bare column reference as the pre-update value and rejects table-
+// qualifying it with table.col.
+func (mysqlDriver) Old(_, col string) string { return col }
@@ -0,0 +1,148 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// OpenRaw opens a plain database/sql handle for dsn ("scheme:rest"),
+// dispatching the driver registration name from the scheme. It bypasses
+// DB's SQLite-specific bootstrapping (PRAGMA table_info, sqlite_master),
+// for tooling such as `store migrate` that needs to address either backend
+// generically by column name rather than through DB's query helpers.
+func OpenRaw(dsn string) (*sql.DB, Driver, error) {
+	scheme, rest := SplitDSN(dsn)
+	drv, err := DriverForScheme(scheme)
+	if err != nil {
+		return nil, nil, err
+	}
+	db, err := sql.Open(drv.Name(), rest)
+	if err != nil {
+		return nil, nil, err
+	}
+	return db, drv, nil
+}
+
+// idInt is the dialect-specific auto-incrementing integer primary key
+// declaration used by coreTableDDL.
+func idInt(drv Driver) string {
+	switch drv.Name() {
+	case "postgres":
+		return "SERIAL PRIMARY KEY"
+	case "mysql":
+		return "INTEGER PRIMARY KEY AUTO_INCREMENT"
+	default:
+		return "INTEGER PRIMARY KEY AUTOINCREMENT"
+	}
+}
+
+// blobType is the dialect-specific binary column type used by coreTableDDL
+// for the media_key/file_sha256/file_enc_sha256 columns. Postgres has no
+// BLOB type; its binary type is bytea.
+func blobType(drv Driver) string {
+	if drv.Name() == "postgres" {
+		return "bytea"
+	}
+	return "BLOB"
+}
+
+// coreTableDDL returns the portable subset of migrateCoreSchema's DDL for
+// drv, so `store migrate` can stand up an empty destination schema before
+// streaming rows. It intentionally excludes messages_fts: full-text search
+// storage is dialect-specific (SQLite FTS5 vs Postgres tsvector+GIN) and is
+// left for the destination backend to (re)index after migration.
+func coreTableDDL(drv Driver) []string {
+	return []string{
+		`CREATE TABLE IF NOT EXISTS chats (
+			jid TEXT PRIMARY KEY,
+			kind TEXT NOT NULL,
+			name TEXT,
+			last_message_ts INTEGER,
+			archived INTEGER NOT NULL DEFAULT 0,
+			pinned INTEGER NOT NULL DEFAULT 0,
+			muted_until INTEGER NOT NULL DEFAULT 0,
+			unread INTEGER NOT NULL DEFAULT 0
+		)`,
+		`CREATE TABLE IF NOT EXISTS contacts (
+			jid TEXT PRIMARY KEY,
+			phone TEXT,
+			push_name TEXT,
+			full_name TEXT,
+			first_name TEXT,
+			business_name TEXT,
+			updated_at INTEGER NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS groups (
+			jid TEXT PRIMARY KEY,
+			name TEXT,
+			owner_jid TEXT,
+			created_ts INTEGER,
+			updated_at INTEGER NOT NULL,
+			is_parent INTEGER NOT NULL DEFAULT 0,
+			linked_parent_jid TEXT
+		)`,
+		`CREATE TABLE IF NOT EXISTS group_participants (
+			group_jid TEXT NOT NULL,
+			user_jid TEXT NOT NULL,
+			role TEXT,
+			updated_at INTEGER NOT NULL,
+			PRIMARY KEY (group_jid, user_jid)
+		)`,
+		`CREATE TABLE IF NOT EXISTS contact_aliases (
+			jid TEXT PRIMARY KEY,
+			alias TEXT NOT NULL,
+			notes TEXT,
+			updated_at INTEGER NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS contact_tags (
+			jid TEXT NOT NULL,
+			tag TEXT NOT NULL,
+			updated_at INTEGER NOT NULL,
+			PRIMARY KEY (jid, tag)
+		)`,
+		`CREATE TABLE IF NOT EXISTS chat_labels (
+			jid TEXT NOT NULL,
+			label TEXT NOT NULL,
+			color TEXT,
+			sort_order INTEGER NOT NULL DEFAULT 0,
+			updated_at INTEGER NOT NULL,
+			PRIMARY KEY (jid, label)
+		)`,
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS messages (
+			rowid %s,
+			chat_jid TEXT NOT NULL,
+			chat_name TEXT,
+			msg_id TEXT NOT NULL,
+			sender_jid TEXT,
+			sender_name TEXT,
+			ts INTEGER NOT NULL,
+			from_me INTEGER NOT NULL,
+			text TEXT,
+			display_text TEXT,
+			media_type TEXT,
+			media_caption TEXT,
+			filename TEXT,
+			mime_type TEXT,
+			direct_path TEXT,
+			media_key %[2]s,
+			file_sha256 %[2]s,
+			file_enc_sha256 %[2]s,
+			file_length INTEGER,
+			local_path TEXT,
+			downloaded_at INTEGER,
+			deleted INTEGER NOT NULL DEFAULT 0,
+			UNIQUE(chat_jid, msg_id)
+		)`, idInt(drv), blobType(drv)),
+	}
+}
+
+// EnsureSchemaFor creates the portable table set on dst if it does not
+// already exist, so `store migrate` can target an empty destination.
+func EnsureSchemaFor(dst *sql.DB, drv Driver) error {
+	for _, ddl := range coreTableDDL(drv) {
+		if _, err := dst.Exec(ddl); err != nil {
+			return fmt.Errorf("create destination schema (%s): %w", drv.Name(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+package store
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SearchQuery is the input to SearchMessages. Text may mix free-text terms
+// with column-scoped tokens ("sender:alice text:invoice") the way mail
+// search boxes do; ParseSearchQuery splits the two apart. The remaining
+// fields narrow the search the same way MessageFilter does for ListMessages.
+type SearchQuery struct {
+	Text      string
+	ChatJID   *string
+	SenderJID *string
+	Since     *time.Time
+	Until     *time.Time
+	FromMe    *bool
+}
+
+// SearchHit is one FTS match: the message plus its rank (lower is more
+// relevant, as bm25 returns) and a highlighted snippet of the matched text.
+// Under the LIKE fallback, which has no equivalent scoring, Rank is
+// zero-valued and Snippet is the unhighlighted message text.
+type SearchHit struct {
+	Message
+	Snippet string
+	Rank    float64
+}
+
+// SearchMessagesResult is the page returned by SearchMessages, plus a token
+// to fetch the next page.
+type SearchMessagesResult struct {
+	Hits          []SearchHit
+	NextPageToken string
+}
+
+// ParseSearchQuery splits raw into free-text terms and column-scoped tokens
+// of the form "field:value" (field one of "sender", "chat", "text"). A bare
+// "text:" token is folded back into the free-text terms, since it exists
+// only so a value containing ":" can be disambiguated from a scope.
+func ParseSearchQuery(raw string) (terms []string, scoped map[string][]string) {
+	scoped = map[string][]string{}
+	for _, tok := range strings.Fields(raw) {
+		if field, value, ok := strings.Cut(tok, ":"); ok && value != "" {
+			switch field {
+			case "sender", "chat":
+				scoped[field] = append(scoped[field], value)
+				continue
+			case "text":
+				terms = append(terms, value)
+				continue
+			}
+		}
+		terms = append(terms, tok)
+	}
+	return terms, scoped
+}
+
+// SearchMessages runs a full-text search over messages_fts, falling back to
+// LIKE when the FTS index is unavailable (d.ftsEnabled is false). Paging uses
+// its own opaque cursor (see searchPageCursor): the ranked path orders by
+// (bm25, ts, msg_id) and the token must encode all three, or the cursor
+// would bear no relation to the order being paged.
+func (d *DB) SearchMessages(ctx context.Context, q SearchQuery, pageSize int, token string) (SearchMessagesResult, error) {
+	if pageSize <= 0 {
+		pageSize = 50
+	}
+
+	terms, scoped := ParseSearchQuery(q.Text)
+
+	var where []string
+	var args []interface{}
+	where = append(where, `COALESCE(m.deleted, 0) = 0`)
+
+	// "chat:"/"sender:" scoped tokens are names (the example is "sender:alice"),
+	// not JIDs, so they're matched against chat_name/sender_name by substring;
+	// q.ChatJID/q.SenderJID are the typed, exact-JID filters used by callers
+	// that already resolved a JID (e.g. `messages search`'s --chat/--sender flags).
+	if names := scoped["chat"]; len(names) > 0 {
+		var ors []string
+		for _, name := range names {
+			ors = append(ors, `LOWER(m.chat_name) LIKE LOWER(?)`)
+			args = append(args, "%"+name+"%")
+		}
+		where = append(where, "("+strings.Join(ors, " OR ")+")")
+	}
+	if q.ChatJID != nil {
+		where = append(where, `m.chat_jid = ?`)
+		args = append(args, *q.ChatJID)
+	}
+
+	if names := scoped["sender"]; len(names) > 0 {
+		var ors []string
+		for _, name := range names {
+			ors = append(ors, `LOWER(m.sender_name) LIKE LOWER(?)`)
+			args = append(args, "%"+name+"%")
+		}
+		where = append(where, "("+strings.Join(ors, " OR ")+")")
+	}
+	if q.SenderJID != nil {
+		where = append(where, `m.sender_jid = ?`)
+		args = append(args, *q.SenderJID)
+	}
+
+	if q.FromMe != nil {
+		where = append(where, `m.from_me = ?`)
+		args = append(args, boolToInt(*q.FromMe))
+	}
+	if q.Since != nil {
+		where = append(where, `m.ts > ?`)
+		args = append(args, unix(*q.Since))
+	}
+	if q.Until != nil {
+		where = append(where, `m.ts < ?`)
+		args = append(args, unix(*q.Until))
+	}
+
+	// withFTS must be known before the token is applied: the ranked path
+	// pages on (bm25, ts, msg_id), not just (ts, msg_id), so the keyset
+	// condition below differs by path.
+	withFTS := len(terms) > 0 && d.ftsEnabled
+
+	var q2 string
+	if withFTS {
+		where = append(where, `messages_fts MATCH ?`)
+		args = append(args, ftsMatchExpr(terms))
+		q2 = `SELECT m.rowid, m.chat_jid, COALESCE(m.chat_name,''), m.msg_id, COALESCE(m.sender_jid,''), COALESCE(m.sender_name,''),
+		             m.ts, m.from_me, COALESCE(m.text,''), COALESCE(m.display_text,''), COALESCE(m.media_type,''), COALESCE(m.media_caption,''),
+		             snippet(messages_fts, -1, '[', ']', '...', 10), bm25(messages_fts)
+		      FROM messages m JOIN messages_fts ON messages_fts.rowid = m.rowid`
+	} else {
+		for _, term := range terms {
+			where = append(where, `(LOWER(m.text) LIKE LOWER(?) OR LOWER(m.display_text) LIKE LOWER(?))`)
+			needle := "%" + term + "%"
+			args = append(args, needle, needle)
+		}
+		q2 = `SELECT m.rowid, m.chat_jid, COALESCE(m.chat_name,''), m.msg_id, COALESCE(m.sender_jid,''), COALESCE(m.sender_name,''),
+		             m.ts, m.from_me, COALESCE(m.text,''), COALESCE(m.display_text,''), COALESCE(m.media_type,''), COALESCE(m.media_caption,'')
+		      FROM messages m`
+	}
+
+	if token != "" {
+		cur, err := decodeSearchPageToken(token)
+		if err != nil {
+			return SearchMessagesResult{}, err
+		}
+		if withFTS && cur.Rank != nil {
+			// Continue strictly after (rank, ts, msg_id) in the same ASC-rank,
+			// DESC-recency order the query sorts by, so a ranked page never
+			// re-emits a higher-rank-but-older hit or skips a lower-rank-but-
+			// newer one the way a bare (ts, msg_id) cursor would.
+			where = append(where, `(bm25(messages_fts) > ? OR (bm25(messages_fts) = ? AND (m.ts < ? OR (m.ts = ? AND m.msg_id < ?))))`)
+			args = append(args, *cur.Rank, *cur.Rank, cur.Timestamp, cur.Timestamp, cur.MsgID)
+		} else {
+			where = append(where, `(m.ts < ? OR (m.ts = ? AND m.msg_id < ?))`)
+			args = append(args, cur.Timestamp, cur.Timestamp, cur.MsgID)
+		}
+	}
+
+	if len(where) > 0 {
+		q2 += " WHERE " + strings.Join(where, " AND ")
+	}
+	if withFTS {
+		// bm25 is negative and lower-is-better; order best matches first, and
+		// break ties by recency so results stay deterministic.
+		q2 += ` ORDER BY bm25(messages_fts) ASC, m.ts DESC, m.msg_id DESC LIMIT ?`
+	} else {
+		q2 += ` ORDER BY m.ts DESC, m.msg_id DESC LIMIT ?`
+	}
+	args = append(args, pageSize+1)
+
+	rows, err := d.sql.QueryContext(ctx, q2, args...)
+	if err != nil {
+		return SearchMessagesResult{}, fmt.Errorf("search messages: %w", err)
+	}
+	defer rows.Close()
+
+	var out []SearchHit
+	for rows.Next() {
+		var h SearchHit
+		var ts int64
+		var fromMe int
+		dest := []interface{}{&h.RowID, &h.ChatJID, &h.ChatName, &h.MsgID, &h.SenderJID, &h.SenderName,
+			&ts, &fromMe, &h.Text, &h.DisplayText, &h.MediaType, &h.MediaCaption}
+		if withFTS {
+			dest = append(dest, &h.Snippet, &h.Rank)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return SearchMessagesResult{}, err
+		}
+		h.Timestamp = fromUnix(ts)
+		h.FromMe = fromMe != 0
+		if !withFTS {
+			h.Snippet = h.Text
+		}
+		out = append(out, h)
+	}
+	if err := rows.Err(); err != nil {
+		return SearchMessagesResult{}, err
+	}
+
+	var next string
+	if len(out) > pageSize {
+		last := out[pageSize-1]
+		out = out[:pageSize]
+		cur := searchPageCursor{Timestamp: unix(last.Timestamp), MsgID: last.MsgID}
+		if withFTS {
+			cur.Rank = &last.Rank
+		}
+		next = encodeSearchPageToken(cur)
+	}
+
+	return SearchMessagesResult{Hits: out, NextPageToken: next}, nil
+}
+
+// searchPageCursor is the decoded form of an opaque SearchMessages page
+// token. Rank is only set (and only compared) when the page it came from
+// was ranked by bm25; a token produced by the LIKE fallback path carries no
+// rank and pages on (Timestamp, MsgID) alone, matching how that path is
+// ordered.
+type searchPageCursor struct {
+	Rank      *float64 `json:"rank,omitempty"`
+	Timestamp int64    `json:"ts"`
+	MsgID     string   `json:"id"`
+}
+
+func encodeSearchPageToken(c searchPageCursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeSearchPageToken(token string) (searchPageCursor, error) {
+	var c searchPageCursor
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return c, fmt.Errorf("decode page token: %w", err)
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, fmt.Errorf("parse page token: %w", err)
+	}
+	return c, nil
+}
@@ -0,0 +1,148 @@
+package store
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// HistoryAnchor identifies a point in a chat's timeline, either by message ID
+// or by a Unix timestamp, the way IRCv3 `draft/chathistory` verbs address a
+// message with either a msgid or a timestamp token.
+type HistoryAnchor struct {
+	MsgID     string
+	Timestamp time.Time
+}
+
+// ParseHistoryAnchor accepts either a bare msg_id or a Unix timestamp
+// (seconds) and returns the anchor in the form GetMessagesBefore/After/Around
+// expect. If tok looks numeric it is treated as a timestamp.
+func ParseHistoryAnchor(tok string) HistoryAnchor {
+	if secs, err := strconv.ParseInt(tok, 10, 64); err == nil {
+		return HistoryAnchor{Timestamp: fromUnix(secs)}
+	}
+	return HistoryAnchor{MsgID: tok}
+}
+
+func (d *DB) resolveAnchor(chatJID string, a HistoryAnchor) (ts int64, msgID string, err error) {
+	if a.MsgID != "" {
+		row := d.sql.QueryRow(`SELECT ts, msg_id FROM messages WHERE chat_jid = ? AND msg_id = ?`, chatJID, a.MsgID)
+		if err := row.Scan(&ts, &msgID); err != nil {
+			return 0, "", fmt.Errorf("resolve anchor msg_id %s: %w", a.MsgID, err)
+		}
+		return ts, msgID, nil
+	}
+	return unix(a.Timestamp), "", nil
+}
+
+// GetMessagesBefore returns up to limit messages in chatJID strictly older
+// than anchor, newest-first, ordered by the stable (ts, msg_id) composite key.
+func (d *DB) GetMessagesBefore(chatJID string, anchor HistoryAnchor, limit int) ([]Message, error) {
+	ts, msgID, err := d.resolveAnchor(chatJID, anchor)
+	if err != nil {
+		return nil, err
+	}
+	q := `SELECT ` + historyColumns + ` FROM messages WHERE chat_jid = ? AND (ts < ? OR (ts = ? AND msg_id < ?))
+	      ORDER BY ts DESC, msg_id DESC LIMIT ?`
+	return d.queryHistory(q, chatJID, ts, ts, msgID, limit)
+}
+
+// GetMessagesAfter returns up to limit messages in chatJID strictly newer
+// than anchor, oldest-first.
+func (d *DB) GetMessagesAfter(chatJID string, anchor HistoryAnchor, limit int) ([]Message, error) {
+	ts, msgID, err := d.resolveAnchor(chatJID, anchor)
+	if err != nil {
+		return nil, err
+	}
+	pred := `(ts > ? OR (ts = ? AND msg_id > ?))`
+	args := []interface{}{chatJID, ts, ts, msgID, limit}
+	if msgID == "" {
+		// A timestamp anchor has no msg_id to break ties on, so every
+		// message at exactly that ts is excluded too.
+		pred = `ts > ?`
+		args = []interface{}{chatJID, ts, limit}
+	}
+	q := `SELECT ` + historyColumns + ` FROM messages WHERE chat_jid = ? AND ` + pred + `
+	      ORDER BY ts ASC, msg_id ASC LIMIT ?`
+	return d.queryHistory(q, args...)
+}
+
+// GetLatestMessages returns the most recent limit messages in chatJID,
+// newest-first.
+func (d *DB) GetLatestMessages(chatJID string, limit int) ([]Message, error) {
+	q := `SELECT ` + historyColumns + ` FROM messages WHERE chat_jid = ? ORDER BY ts DESC, msg_id DESC LIMIT ?`
+	return d.queryHistory(q, chatJID, limit)
+}
+
+// GetMessagesAround returns up to limit/2 messages before anchor and up to
+// limit-limit/2 after it, plus the anchor message itself, ordered oldest-first.
+func (d *DB) GetMessagesAround(chatJID string, anchor HistoryAnchor, limit int) ([]Message, error) {
+	ts, msgID, err := d.resolveAnchor(chatJID, anchor)
+	if err != nil {
+		return nil, err
+	}
+	half := limit / 2
+
+	before, err := d.queryHistory(
+		`SELECT `+historyColumns+` FROM messages WHERE chat_jid = ? AND (ts < ? OR (ts = ? AND msg_id < ?)) ORDER BY ts DESC, msg_id DESC LIMIT ?`,
+		chatJID, ts, ts, msgID, half,
+	)
+	if err != nil {
+		return nil, err
+	}
+	anchorMsgs, err := d.queryHistory(
+		`SELECT `+historyColumns+` FROM messages WHERE chat_jid = ? AND ts = ? AND msg_id = ?`,
+		chatJID, ts, msgID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	after, err := d.queryHistory(
+		`SELECT `+historyColumns+` FROM messages WHERE chat_jid = ? AND (ts > ? OR (ts = ? AND msg_id > ?)) ORDER BY ts ASC, msg_id ASC LIMIT ?`,
+		chatJID, ts, ts, msgID, limit-half,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]Message, 0, len(before)+len(anchorMsgs)+len(after))
+	for i := len(before) - 1; i >= 0; i-- {
+		out = append(out, before[i])
+	}
+	out = append(out, anchorMsgs...)
+	out = append(out, after...)
+	return out, nil
+}
+
+// GetMessagesBetween returns up to limit messages in chatJID with ts in
+// [start, end], oldest-first.
+func (d *DB) GetMessagesBetween(chatJID string, start, end time.Time, limit int) ([]Message, error) {
+	q := `SELECT ` + historyColumns + ` FROM messages WHERE chat_jid = ? AND ts >= ? AND ts <= ? ORDER BY ts ASC, msg_id ASC LIMIT ?`
+	return d.queryHistory(q, chatJID, unix(start), unix(end), limit)
+}
+
+const historyColumns = `rowid, chat_jid, COALESCE(chat_name,''), msg_id, COALESCE(sender_jid,''), COALESCE(sender_name,''),
+	       ts, from_me, COALESCE(text,''), COALESCE(display_text,''), COALESCE(media_type,''), COALESCE(media_caption,'')`
+
+func (d *DB) queryHistory(q string, args ...interface{}) ([]Message, error) {
+	rows, err := d.sql.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Message
+	for rows.Next() {
+		var m Message
+		var ts int64
+		var fromMe int
+		if err := rows.Scan(&m.RowID, &m.ChatJID, &m.ChatName, &m.MsgID, &m.SenderJID, &m.SenderName,
+			&ts, &fromMe, &m.Text, &m.DisplayText, &m.MediaType, &m.MediaCaption); err != nil {
+			return nil, err
+		}
+		m.Timestamp = fromUnix(ts)
+		m.FromMe = fromMe != 0
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
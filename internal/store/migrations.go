@@ -20,6 +20,9 @@ var schemaMigrations = []migration{
 	{version: 3, name: "messages fts", up: migrateMessagesFTS},
 	{version: 4, name: "chat state columns", up: migrateChatState},
 	{version: 5, name: "group community columns", up: migrateGroupCommunity},
+	{version: 6, name: "messages deleted column", up: migrateMessagesDeleted},
+	{version: 7, name: "saved searches", up: migrateSavedSearches},
+	{version: 8, name: "chat labels", up: migrateChatLabels},
 }
 
 func (d *DB) ensureSchema() error {
@@ -252,8 +255,22 @@ func migrateMessagesFTS(d *DB) error {
 	return nil
 }
 
+// tableExists reports whether table is present, checking the dialect's own
+// catalog: sqlite_master for SQLite, information_schema for Postgres/MySQL.
+// This keeps ensureSchema's migrations introspectable once DB talks to a
+// non-SQLite backend; Open itself still only ever constructs a sqlite3
+// *sql.DB (see driver.go), so that remains the last wire-up needed before
+// these migrations can run against Postgres/MySQL for real.
 func (d *DB) tableExists(table string) (bool, error) {
-	row := d.sql.QueryRow(`SELECT 1 FROM sqlite_master WHERE name = ? AND type IN ('table','view')`, table)
+	drv := d.driver()
+	var q string
+	switch drv.Name() {
+	case "postgres", "mysql":
+		q = `SELECT 1 FROM information_schema.tables WHERE table_name = ?`
+	default:
+		q = `SELECT 1 FROM sqlite_master WHERE name = ? AND type IN ('table','view')`
+	}
+	row := d.sql.QueryRow(drv.Rebind(q), table)
 	var one int
 	if err := row.Scan(&one); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -306,7 +323,79 @@ func migrateGroupCommunity(d *DB) error {
 	return nil
 }
 
+func migrateMessagesDeleted(d *DB) error {
+	has, err := d.tableHasColumn("messages", "deleted")
+	if err != nil {
+		return err
+	}
+	if has {
+		return nil
+	}
+	if _, err := d.sql.Exec(`ALTER TABLE messages ADD COLUMN deleted INTEGER NOT NULL DEFAULT 0`); err != nil {
+		return fmt.Errorf("add deleted column: %w", err)
+	}
+	return nil
+}
+
+func migrateSavedSearches(d *DB) error {
+	if _, err := d.sql.Exec(`
+		CREATE TABLE IF NOT EXISTS saved_searches (
+			name TEXT PRIMARY KEY,
+			kind TEXT NOT NULL, -- "messages" or "chats"
+			filter_json TEXT NOT NULL,
+			created_at INTEGER NOT NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("create saved_searches table: %w", err)
+	}
+	return nil
+}
+
+func migrateChatLabels(d *DB) error {
+	if _, err := d.sql.Exec(`
+		CREATE TABLE IF NOT EXISTS chat_labels (
+			jid TEXT NOT NULL,
+			label TEXT NOT NULL,
+			color TEXT,
+			sort_order INTEGER NOT NULL DEFAULT 0,
+			updated_at INTEGER NOT NULL,
+			PRIMARY KEY (jid, label)
+		)
+	`); err != nil {
+		return fmt.Errorf("create chat_labels table: %w", err)
+	}
+
+	has, err := d.tableHasColumn("chat_labels", "sort_order")
+	if err != nil {
+		return err
+	}
+	if !has {
+		if _, err := d.sql.Exec(`ALTER TABLE chat_labels ADD COLUMN sort_order INTEGER NOT NULL DEFAULT 0`); err != nil {
+			return fmt.Errorf("add sort_order column: %w", err)
+		}
+	}
+	return nil
+}
+
+// tableHasColumn reports whether table has column, using sqlite's
+// PRAGMA table_info on SQLite and information_schema.columns on
+// Postgres/MySQL (neither of which understands PRAGMA).
 func (d *DB) tableHasColumn(table, column string) (bool, error) {
+	drv := d.driver()
+	if drv.Name() != "sqlite" {
+		row := d.sql.QueryRow(drv.Rebind(
+			`SELECT 1 FROM information_schema.columns WHERE table_name = ? AND column_name = ?`,
+		), table, column)
+		var one int
+		if err := row.Scan(&one); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return false, nil
+			}
+			return false, err
+		}
+		return true, nil
+	}
+
 	rows, err := d.sql.Query("PRAGMA table_info(" + table + ")")
 	if err != nil {
 		return false, err
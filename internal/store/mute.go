@@ -0,0 +1,41 @@
+package store
+
+import "time"
+
+// ListExpiredMutes returns the JIDs of chats whose mute has a finite
+// expiry that has passed as of now. Chats muted forever (muted_until = -1)
+// never appear here.
+func (d *DB) ListExpiredMutes(now time.Time) ([]string, error) {
+	rows, err := d.sql.Query(`SELECT jid FROM chats WHERE muted_until > 0 AND muted_until <= ?`, now.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []string
+	for rows.Next() {
+		var jid string
+		if err := rows.Scan(&jid); err != nil {
+			return nil, err
+		}
+		out = append(out, jid)
+	}
+	return out, rows.Err()
+}
+
+// MutedRemaining reports how long c's mute has left. ok is false if c is
+// not muted; forever is true if c is muted with no expiry.
+func (c Chat) MutedRemaining(now time.Time) (remaining time.Duration, forever bool, ok bool) {
+	switch {
+	case c.MutedUntil == 0:
+		return 0, false, false
+	case c.MutedUntil < 0:
+		return 0, true, true
+	default:
+		until := fromUnix(c.MutedUntil)
+		if !until.After(now) {
+			return 0, false, false
+		}
+		return until.Sub(now), false, true
+	}
+}
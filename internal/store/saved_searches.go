@@ -0,0 +1,118 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SavedSearch is a persisted MessageFilter or ChatListFilter, addressable by
+// name, so that "smart folders" like search run <name> don't need the
+// filter re-specified on every invocation. Kind says which of Filter/
+// ChatFilter is populated ("messages" or "chats").
+type SavedSearch struct {
+	Name       string
+	Kind       string
+	Filter     MessageFilter
+	ChatFilter ChatListFilter
+	CreatedAt  time.Time
+}
+
+// SaveSearch persists a MessageFilter under name, overwriting any existing
+// search with the same name.
+func (d *DB) SaveSearch(name string, filter MessageFilter) error {
+	return d.saveSearch(name, "messages", filter)
+}
+
+// SaveChatSearch persists a ChatListFilter under name, overwriting any
+// existing search with the same name, so chats-list smart folders (e.g.
+// "unread from tagged:family") can be re-run the same way message searches are.
+func (d *DB) SaveChatSearch(name string, filter ChatListFilter) error {
+	return d.saveSearch(name, "chats", filter)
+}
+
+func (d *DB) saveSearch(name, kind string, filter interface{}) error {
+	b, err := json.Marshal(filter)
+	if err != nil {
+		return fmt.Errorf("encode filter: %w", err)
+	}
+	_, err = d.sql.Exec(`
+		INSERT INTO saved_searches(name, kind, filter_json, created_at)
+		VALUES(?, ?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET kind=excluded.kind, filter_json=excluded.filter_json, created_at=excluded.created_at
+	`, name, kind, string(b), time.Now().UTC().Unix())
+	return err
+}
+
+// ListSavedSearches returns every saved search (both kinds), most recently
+// created first.
+func (d *DB) ListSavedSearches() ([]SavedSearch, error) {
+	rows, err := d.sql.Query(`SELECT name, kind, filter_json, created_at FROM saved_searches ORDER BY created_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []SavedSearch
+	for rows.Next() {
+		var name, kind, filterJSON string
+		var created int64
+		if err := rows.Scan(&name, &kind, &filterJSON, &created); err != nil {
+			return nil, err
+		}
+		s := SavedSearch{Name: name, Kind: kind, CreatedAt: fromUnix(created)}
+		if kind == "chats" {
+			if err := json.Unmarshal([]byte(filterJSON), &s.ChatFilter); err != nil {
+				return nil, fmt.Errorf("decode filter for %q: %w", name, err)
+			}
+		} else {
+			if err := json.Unmarshal([]byte(filterJSON), &s.Filter); err != nil {
+				return nil, fmt.Errorf("decode filter for %q: %w", name, err)
+			}
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+// RunSavedSearch loads the saved message search named name and runs it
+// through ListMessages, returning up to limit messages.
+func (d *DB) RunSavedSearch(ctx context.Context, name string, limit int) (ListMessagesResult, error) {
+	row := d.sql.QueryRow(`SELECT filter_json FROM saved_searches WHERE name = ? AND kind = 'messages'`, name)
+	var filterJSON string
+	if err := row.Scan(&filterJSON); err != nil {
+		return ListMessagesResult{}, fmt.Errorf("saved search %q: %w", name, err)
+	}
+	var f MessageFilter
+	if err := json.Unmarshal([]byte(filterJSON), &f); err != nil {
+		return ListMessagesResult{}, fmt.Errorf("decode filter for %q: %w", name, err)
+	}
+	return d.ListMessages(ctx, f, limit, "")
+}
+
+// RunSavedChatSearch loads the saved chat-list search named name and runs it
+// through ListChats. limitOverride, if positive, replaces the filter's own
+// Limit (so `search run-chats <name> --limit N` can widen/narrow a saved page
+// size without re-saving the search).
+func (d *DB) RunSavedChatSearch(name string, limitOverride int) ([]Chat, error) {
+	row := d.sql.QueryRow(`SELECT filter_json FROM saved_searches WHERE name = ? AND kind = 'chats'`, name)
+	var filterJSON string
+	if err := row.Scan(&filterJSON); err != nil {
+		return nil, fmt.Errorf("saved chat search %q: %w", name, err)
+	}
+	var f ChatListFilter
+	if err := json.Unmarshal([]byte(filterJSON), &f); err != nil {
+		return nil, fmt.Errorf("decode filter for %q: %w", name, err)
+	}
+	if limitOverride > 0 {
+		f.Limit = limitOverride
+	}
+	return d.ListChats(f)
+}
+
+// RemoveSavedSearch deletes a saved search by name.
+func (d *DB) RemoveSavedSearch(name string) error {
+	_, err := d.sql.Exec(`DELETE FROM saved_searches WHERE name = ?`, name)
+	return err
+}
@@ -0,0 +1,242 @@
+package store
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MessageFilter selects messages across chats for ListMessages. Nil fields are
+// not filtered on. Modeled on the rich filter-object pattern (each criterion
+// is optional and independently composable) rather than a single query string.
+type MessageFilter struct {
+	SearchStringFTS *[]string
+	SenderJID       *[]string
+	ChatJID         *[]string
+	SenderTags      *[]string // match messages whose sender JID carries any of these contact_tags
+	FromMe          *bool
+	TimestampAfter  *time.Time
+	TimestampBefore *time.Time
+	HasMedia        *bool
+	IncludeDeleted  bool
+}
+
+// pageCursor is the decoded form of an opaque ListMessages page token. Dir
+// records which way the cursor pages when fed back in as token: "next"
+// continues toward older messages (the default, also used when Dir is
+// empty), "prev" continues back toward newer ones. ListMessages returns a
+// NextPageToken/PrevPageToken pair each carrying the right Dir, so callers
+// can page in either direction without a separate API.
+type pageCursor struct {
+	Timestamp int64  `json:"ts"`
+	MsgID     string `json:"id"`
+	Dir       string `json:"dir,omitempty"` // "next" or "prev"
+}
+
+func encodePageToken(c pageCursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodePageToken(token string) (pageCursor, error) {
+	var c pageCursor
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return c, fmt.Errorf("decode page token: %w", err)
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, fmt.Errorf("parse page token: %w", err)
+	}
+	return c, nil
+}
+
+// ListMessagesResult is the page returned by ListMessages, plus tokens to
+// fetch the next (older) and previous (newer) pages. Either is empty once
+// there is nothing further in that direction.
+type ListMessagesResult struct {
+	Messages      []Message
+	NextPageToken string
+	PrevPageToken string
+}
+
+// ListMessages composes the WHERE/JOIN clauses for f dynamically and returns
+// up to pageSize messages ordered newest-first. token (if set) resumes from
+// a NextPageToken or PrevPageToken returned by a previous call, paging
+// forward (older) or backward (newer) deterministically according to the
+// token's encoded direction (see pageCursor). SearchStringFTS terms are
+// quoted and AND-joined into an FTS5 MATCH expression against messages_fts;
+// if the FTS index is unavailable the terms fall back to LIKE.
+func (d *DB) ListMessages(ctx context.Context, f MessageFilter, pageSize int, token string) (ListMessagesResult, error) {
+	if pageSize <= 0 {
+		pageSize = 50
+	}
+
+	var joins []string
+	var where []string
+	var args []interface{}
+
+	if !f.IncludeDeleted {
+		where = append(where, `COALESCE(m.deleted, 0) = 0`)
+	}
+	if f.ChatJID != nil && len(*f.ChatJID) > 0 {
+		where = append(where, `m.chat_jid IN (`+placeholders(len(*f.ChatJID))+`)`)
+		for _, jid := range *f.ChatJID {
+			args = append(args, jid)
+		}
+	}
+	if f.SenderJID != nil && len(*f.SenderJID) > 0 {
+		where = append(where, `m.sender_jid IN (`+placeholders(len(*f.SenderJID))+`)`)
+		for _, jid := range *f.SenderJID {
+			args = append(args, jid)
+		}
+	}
+	if f.SenderTags != nil && len(*f.SenderTags) > 0 {
+		where = append(where, `EXISTS (SELECT 1 FROM contact_tags t WHERE t.jid = m.sender_jid AND t.tag IN (`+placeholders(len(*f.SenderTags))+`))`)
+		for _, tag := range *f.SenderTags {
+			args = append(args, tag)
+		}
+	}
+	if f.FromMe != nil {
+		where = append(where, `m.from_me = ?`)
+		args = append(args, boolToInt(*f.FromMe))
+	}
+	if f.TimestampAfter != nil {
+		where = append(where, `m.ts > ?`)
+		args = append(args, unix(*f.TimestampAfter))
+	}
+	if f.TimestampBefore != nil {
+		where = append(where, `m.ts < ?`)
+		args = append(args, unix(*f.TimestampBefore))
+	}
+	if f.HasMedia != nil {
+		if *f.HasMedia {
+			where = append(where, `m.media_type IS NOT NULL AND m.media_type != ''`)
+		} else {
+			where = append(where, `(m.media_type IS NULL OR m.media_type = '')`)
+		}
+	}
+	if f.SearchStringFTS != nil && len(*f.SearchStringFTS) > 0 {
+		if d.ftsEnabled {
+			joins = append(joins, `JOIN messages_fts ON messages_fts.rowid = m.rowid`)
+			where = append(where, `messages_fts MATCH ?`)
+			args = append(args, ftsMatchExpr(*f.SearchStringFTS))
+		} else {
+			for _, term := range *f.SearchStringFTS {
+				where = append(where, `(LOWER(m.text) LIKE LOWER(?) OR LOWER(m.display_text) LIKE LOWER(?))`)
+				needle := "%" + term + "%"
+				args = append(args, needle, needle)
+			}
+		}
+	}
+
+	var cur pageCursor
+	haveCursor := token != ""
+	backward := false
+	if haveCursor {
+		var err error
+		cur, err = decodePageToken(token)
+		if err != nil {
+			return ListMessagesResult{}, err
+		}
+		backward = cur.Dir == "prev"
+		if backward {
+			where = append(where, `(m.ts > ? OR (m.ts = ? AND m.msg_id > ?))`)
+		} else {
+			where = append(where, `(m.ts < ? OR (m.ts = ? AND m.msg_id < ?))`)
+		}
+		args = append(args, cur.Timestamp, cur.Timestamp, cur.MsgID)
+	}
+
+	q := `SELECT m.rowid, m.chat_jid, COALESCE(m.chat_name,''), m.msg_id, COALESCE(m.sender_jid,''), COALESCE(m.sender_name,''),
+	             m.ts, m.from_me, COALESCE(m.text,''), COALESCE(m.display_text,''), COALESCE(m.media_type,''), COALESCE(m.media_caption,'')
+	      FROM messages m ` + strings.Join(joins, " ")
+	if len(where) > 0 {
+		q += " WHERE " + strings.Join(where, " AND ")
+	}
+	// Backward pages are fetched oldest-first (closest to the cursor first)
+	// so the LIMIT n+1 probe finds the newest-ward boundary; they're
+	// reversed below to keep the returned page newest-first either way.
+	if backward {
+		q += ` ORDER BY m.ts ASC, m.msg_id ASC LIMIT ?`
+	} else {
+		q += ` ORDER BY m.ts DESC, m.msg_id DESC LIMIT ?`
+	}
+	args = append(args, pageSize+1)
+
+	rows, err := d.sql.QueryContext(ctx, q, args...)
+	if err != nil {
+		return ListMessagesResult{}, err
+	}
+	defer rows.Close()
+
+	var out []Message
+	for rows.Next() {
+		var m Message
+		var ts int64
+		var fromMe int
+		if err := rows.Scan(&m.RowID, &m.ChatJID, &m.ChatName, &m.MsgID, &m.SenderJID, &m.SenderName,
+			&ts, &fromMe, &m.Text, &m.DisplayText, &m.MediaType, &m.MediaCaption); err != nil {
+			return ListMessagesResult{}, err
+		}
+		m.Timestamp = fromUnix(ts)
+		m.FromMe = fromMe != 0
+		out = append(out, m)
+	}
+	if err := rows.Err(); err != nil {
+		return ListMessagesResult{}, err
+	}
+
+	hasMore := len(out) > pageSize
+	if hasMore {
+		out = out[:pageSize]
+	}
+	if backward {
+		// The query ran oldest-first to probe the newest-ward boundary;
+		// reverse so the page reads newest-first like every other page.
+		for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+			out[i], out[j] = out[j], out[i]
+		}
+	}
+
+	// hasOlder/hasNewer decide which tokens to emit: the LIMIT n+1 probe only
+	// tells us about the direction we just paged in, but having arrived via
+	// any cursor at all guarantees there is more data in the other direction
+	// (wherever we came from).
+	hasOlder := (!backward && hasMore) || (backward && haveCursor)
+	hasNewer := (backward && hasMore) || (!backward && haveCursor)
+
+	var next, prev string
+	if len(out) > 0 {
+		if hasOlder {
+			oldest := out[len(out)-1]
+			next = encodePageToken(pageCursor{Timestamp: unix(oldest.Timestamp), MsgID: oldest.MsgID, Dir: "next"})
+		}
+		if hasNewer {
+			newest := out[0]
+			prev = encodePageToken(pageCursor{Timestamp: unix(newest.Timestamp), MsgID: newest.MsgID, Dir: "prev"})
+		}
+	}
+
+	return ListMessagesResult{Messages: out, NextPageToken: next, PrevPageToken: prev}, nil
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+// ftsMatchExpr quotes each term individually and ANDs them together so that
+// terms containing FTS5 operator characters are treated as literal text.
+func ftsMatchExpr(terms []string) string {
+	quoted := make([]string, 0, len(terms))
+	for _, t := range terms {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+		quoted = append(quoted, `"`+strings.ReplaceAll(t, `"`, `""`)+`"`)
+	}
+	return strings.Join(quoted, " AND ")
+}
@@ -0,0 +1,27 @@
+package store
+
+import "testing"
+
+func TestPageToken_RoundTrip(t *testing.T) {
+	cases := []pageCursor{
+		{Timestamp: 1700000000, MsgID: "abc", Dir: "next"},
+		{Timestamp: 1700000001, MsgID: "def", Dir: "prev"},
+		{Timestamp: 1700000002, MsgID: "ghi"}, // Dir omitted: must decode as "next"-equivalent (empty)
+	}
+	for _, c := range cases {
+		token := encodePageToken(c)
+		got, err := decodePageToken(token)
+		if err != nil {
+			t.Fatalf("decodePageToken(%q): %v", token, err)
+		}
+		if got != c {
+			t.Errorf("round-trip mismatch: got %+v, want %+v", got, c)
+		}
+	}
+}
+
+func TestDecodePageToken_RejectsGarbage(t *testing.T) {
+	if _, err := decodePageToken("not-base64!!"); err == nil {
+		t.Fatalf("expected decodePageToken to reject invalid base64")
+	}
+}
@@ -0,0 +1,137 @@
+package wa
+
+import (
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// IncomingEvent is wacli's translation of a whatsmeow event into the shape
+// the IPC/provisioning layers publish, so those packages never need to
+// import whatsmeow themselves. Type mirrors ipc.Event's Type values:
+// "message", "message_edit", "message_revoke", "receipt", "chat_state",
+// "presence", "connect", "disconnect".
+type IncomingEvent struct {
+	Type    string
+	ChatJID string
+	Data    any
+}
+
+// OnEvent registers fn to be called, translated into an IncomingEvent, for
+// every whatsmeow event this package surfaces to IPC subscribers. Safe to
+// call any time after the client has been constructed, even before Connect,
+// since AddEventHandler only registers a callback.
+func (c *Client) OnEvent(fn func(IncomingEvent)) {
+	c.mu.Lock()
+	cli := c.client
+	c.mu.Unlock()
+	if cli == nil {
+		return
+	}
+	cli.AddEventHandler(func(raw interface{}) {
+		if evt, ok := translateEvent(raw); ok {
+			fn(evt)
+		}
+	})
+}
+
+func translateEvent(raw interface{}) (IncomingEvent, bool) {
+	switch evt := raw.(type) {
+	case *events.Message:
+		return messageIncomingEvent(evt), true
+	case *events.Receipt:
+		return IncomingEvent{
+			Type:    "receipt",
+			ChatJID: evt.Chat.String(),
+			Data: map[string]any{
+				"sender":    evt.Sender.String(),
+				"msg_ids":   evt.MessageIDs,
+				"kind":      string(evt.Type),
+				"timestamp": evt.Timestamp,
+			},
+		}, true
+	case *events.Presence:
+		return IncomingEvent{
+			Type:    "presence",
+			ChatJID: evt.From.String(),
+			Data: map[string]any{
+				"unavailable": evt.Unavailable,
+				"last_seen":   evt.LastSeen,
+			},
+		}, true
+	case *events.ChatPresence:
+		return IncomingEvent{
+			Type:    "chat_state",
+			ChatJID: evt.Chat.String(),
+			Data: map[string]any{
+				"sender": evt.Sender.String(),
+				"state":  string(evt.State),
+				"media":  string(evt.Media),
+			},
+		}, true
+	case *events.Connected:
+		return IncomingEvent{Type: "connect"}, true
+	case *events.Disconnected:
+		return IncomingEvent{Type: "disconnect"}, true
+	default:
+		return IncomingEvent{}, false
+	}
+}
+
+// messageIncomingEvent tells apart a plain incoming message, an edit, and a
+// revocation, the three shapes whatsmeow delivers through events.Message.
+func messageIncomingEvent(evt *events.Message) IncomingEvent {
+	chatJID := evt.Info.Chat.String()
+	base := map[string]any{
+		"msg_id":      evt.Info.ID,
+		"sender":      evt.Info.Sender.String(),
+		"sender_name": evt.Info.PushName,
+		"from_me":     evt.Info.IsFromMe,
+		"timestamp":   evt.Info.Timestamp,
+	}
+
+	if revokedID := revokedMessageID(evt.Message); revokedID != "" {
+		base["revoked_msg_id"] = revokedID
+		return IncomingEvent{Type: "message_revoke", ChatJID: chatJID, Data: base}
+	}
+
+	base["text"] = messageText(evt.Message)
+	if evt.Info.Edit != types.EditAttributeEmpty {
+		return IncomingEvent{Type: "message_edit", ChatJID: chatJID, Data: base}
+	}
+	return IncomingEvent{Type: "message", ChatJID: chatJID, Data: base}
+}
+
+// revokedMessageID returns the ID of the message msg revokes, or "" if msg
+// is not a revocation.
+func revokedMessageID(msg *waE2E.Message) string {
+	if protocolMsg := msg.GetProtocolMessage(); protocolMsg != nil && protocolMsg.GetType() == waE2E.ProtocolMessage_REVOKE {
+		return protocolMsg.GetKey().GetID()
+	}
+	return ""
+}
+
+// messageText extracts the displayable text from the handful of message
+// kinds that carry one, mirroring the minimal text extraction bridges like
+// mautrix-whatsapp do for incoming events.
+func messageText(msg *waE2E.Message) string {
+	if msg == nil {
+		return ""
+	}
+	if c := msg.GetConversation(); c != "" {
+		return c
+	}
+	if ext := msg.GetExtendedTextMessage(); ext != nil {
+		return ext.GetText()
+	}
+	if img := msg.GetImageMessage(); img != nil {
+		return img.GetCaption()
+	}
+	if vid := msg.GetVideoMessage(); vid != nil {
+		return vid.GetCaption()
+	}
+	if doc := msg.GetDocumentMessage(); doc != nil {
+		return doc.GetCaption()
+	}
+	return ""
+}
@@ -0,0 +1,175 @@
+package wa
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waCommon"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// SendFile uploads the file at path and sends it to target as an image,
+// video, audio, or document message, with the kind auto-detected from
+// mimetype (falling back to the file extension) the way mdtest, whatsmeow's
+// reference CLI, does.
+func (c *Client) SendFile(ctx context.Context, target types.JID, path, caption, mimetype string) (types.MessageID, error) {
+	c.mu.Lock()
+	cli := c.client
+	c.mu.Unlock()
+	if cli == nil || !cli.IsConnected() {
+		return "", fmt.Errorf("not connected")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read file: %w", err)
+	}
+
+	if mimetype == "" {
+		mimetype = mime.TypeByExtension(filepath.Ext(path))
+	}
+	if mimetype == "" {
+		mimetype = "application/octet-stream"
+	}
+
+	mediaType := mediaTypeForMime(mimetype)
+	uploaded, err := cli.Upload(ctx, data, mediaType)
+	if err != nil {
+		return "", fmt.Errorf("upload: %w", err)
+	}
+
+	msg := buildMediaMessage(mediaType, uploaded, mimetype, caption, filepath.Base(path))
+	resp, err := cli.SendMessage(ctx, target, msg)
+	if err != nil {
+		return "", fmt.Errorf("send: %w", err)
+	}
+	return resp.ID, nil
+}
+
+func mediaTypeForMime(mimetype string) whatsmeow.MediaType {
+	switch {
+	case strings.HasPrefix(mimetype, "image/"):
+		return whatsmeow.MediaImage
+	case strings.HasPrefix(mimetype, "video/"):
+		return whatsmeow.MediaVideo
+	case strings.HasPrefix(mimetype, "audio/"):
+		return whatsmeow.MediaAudio
+	default:
+		return whatsmeow.MediaDocument
+	}
+}
+
+func buildMediaMessage(mediaType whatsmeow.MediaType, up whatsmeow.UploadResponse, mimetype, caption, filename string) *waE2E.Message {
+	switch mediaType {
+	case whatsmeow.MediaImage:
+		return &waE2E.Message{ImageMessage: &waE2E.ImageMessage{
+			Caption:       proto.String(caption),
+			Mimetype:      proto.String(mimetype),
+			URL:           proto.String(up.URL),
+			DirectPath:    proto.String(up.DirectPath),
+			MediaKey:      up.MediaKey,
+			FileEncSHA256: up.FileEncSHA256,
+			FileSHA256:    up.FileSHA256,
+			FileLength:    proto.Uint64(up.FileLength),
+		}}
+	case whatsmeow.MediaVideo:
+		return &waE2E.Message{VideoMessage: &waE2E.VideoMessage{
+			Caption:       proto.String(caption),
+			Mimetype:      proto.String(mimetype),
+			URL:           proto.String(up.URL),
+			DirectPath:    proto.String(up.DirectPath),
+			MediaKey:      up.MediaKey,
+			FileEncSHA256: up.FileEncSHA256,
+			FileSHA256:    up.FileSHA256,
+			FileLength:    proto.Uint64(up.FileLength),
+		}}
+	case whatsmeow.MediaAudio:
+		return &waE2E.Message{AudioMessage: &waE2E.AudioMessage{
+			Mimetype:      proto.String(mimetype),
+			URL:           proto.String(up.URL),
+			DirectPath:    proto.String(up.DirectPath),
+			MediaKey:      up.MediaKey,
+			FileEncSHA256: up.FileEncSHA256,
+			FileSHA256:    up.FileSHA256,
+			FileLength:    proto.Uint64(up.FileLength),
+		}}
+	default:
+		return &waE2E.Message{DocumentMessage: &waE2E.DocumentMessage{
+			Caption:       proto.String(caption),
+			Mimetype:      proto.String(mimetype),
+			FileName:      proto.String(filename),
+			URL:           proto.String(up.URL),
+			DirectPath:    proto.String(up.DirectPath),
+			MediaKey:      up.MediaKey,
+			FileEncSHA256: up.FileEncSHA256,
+			FileSHA256:    up.FileSHA256,
+			FileLength:    proto.Uint64(up.FileLength),
+		}}
+	}
+}
+
+// SendReaction sends (or, with an empty emoji, clears) an emoji reaction to
+// an existing message.
+func (c *Client) SendReaction(ctx context.Context, target types.JID, msgID types.MessageID, fromMe bool, emoji string) error {
+	c.mu.Lock()
+	cli := c.client
+	c.mu.Unlock()
+	if cli == nil || !cli.IsConnected() {
+		return fmt.Errorf("not connected")
+	}
+
+	msg := &waE2E.Message{ReactionMessage: &waE2E.ReactionMessage{
+		Key: &waCommon.MessageKey{
+			RemoteJID: proto.String(target.String()),
+			FromMe:    proto.Bool(fromMe),
+			ID:        proto.String(string(msgID)),
+		},
+		Text:              proto.String(emoji),
+		SenderTimestampMS: proto.Int64(time.Now().UnixMilli()),
+	}}
+	_, err := cli.SendMessage(ctx, target, msg)
+	return err
+}
+
+// SendLocation sends a one-off (non-live) location pin.
+func (c *Client) SendLocation(ctx context.Context, target types.JID, lat, lng float64, name string) error {
+	c.mu.Lock()
+	cli := c.client
+	c.mu.Unlock()
+	if cli == nil || !cli.IsConnected() {
+		return fmt.Errorf("not connected")
+	}
+
+	msg := &waE2E.Message{LocationMessage: &waE2E.LocationMessage{
+		DegreesLatitude:  proto.Float64(lat),
+		DegreesLongitude: proto.Float64(lng),
+		Name:             proto.String(name),
+	}}
+	_, err := cli.SendMessage(ctx, target, msg)
+	return err
+}
+
+// SetPresence broadcasts our own global availability (online/offline) to
+// contacts, independent of any per-chat typing/recording indicator.
+func (c *Client) SetPresence(ctx context.Context, available bool) error {
+	c.mu.Lock()
+	cli := c.client
+	c.mu.Unlock()
+	if cli == nil || !cli.IsConnected() {
+		return fmt.Errorf("not connected")
+	}
+
+	presence := types.PresenceUnavailable
+	if available {
+		presence = types.PresenceAvailable
+	}
+	return cli.SendPresence(presence)
+}